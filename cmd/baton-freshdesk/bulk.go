@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	"github.com/conductorone/baton-freshdesk/pkg/connector"
+	"github.com/conductorone/baton-sdk/pkg/field"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	bulkFile       = "file"
+	bulkAccount    = "account"
+	bulkOutput     = "output"
+	bulkJobTimeout = "job-timeout"
+
+	bulkColumnEmail       = "email"
+	bulkColumnName        = "name"
+	bulkColumnLicenseType = "license_type"
+	bulkColumnTicketScope = "ticket_scope"
+	bulkColumnRoles       = "roles"
+	bulkColumnGroups      = "groups"
+)
+
+var bulkColumns = []string{bulkColumnEmail, bulkColumnName, bulkColumnLicenseType, bulkColumnTicketScope, bulkColumnRoles, bulkColumnGroups}
+
+// newBulkCreateAgentsCommand creates the `bulk-create-agents` subcommand, which creates the agents
+// listed in a CSV file in one Freshdesk background job and writes the result of every agent as CSV.
+func newBulkCreateAgentsCommand(ctx context.Context, v *viper.Viper) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bulk-create-agents",
+		Short: "Create the agents listed in a CSV file",
+		Long: "Create the agents listed in a CSV file. The header row names the columns: " + strings.Join(bulkColumns, ", ") +
+			". Only email is required; roles and groups hold names or IDs separated by semicolons.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for _, f := range ConfigurationFields {
+				err := v.BindPFlag(f.FieldName, cmd.Flags().Lookup(f.FieldName))
+				if err != nil {
+					return err
+				}
+			}
+
+			err := field.Validate(field.NewConfiguration(ConfigurationFields, FieldRelationships...), v)
+			if err != nil {
+				return err
+			}
+
+			file, _ := cmd.Flags().GetString(bulkFile)
+			accountDomain, _ := cmd.Flags().GetString(bulkAccount)
+			output, _ := cmd.Flags().GetString(bulkOutput)
+			jobTimeout, _ := cmd.Flags().GetDuration(bulkJobTimeout)
+
+			in, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			agents, err := readBulkAgentsCSV(in)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+
+			cb, err := newConnector(ctx, v)
+			if err != nil {
+				return err
+			}
+
+			backoff := client.DefaultJobBackoff
+			backoff.Timeout = jobTimeout
+
+			results, err := cb.BulkCreateAgents(ctx, accountDomain, agents, backoff)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+
+			err = writeBulkResultsCSV(w, results)
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, result := range results {
+				if !result.Success {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d agents were not created", failed, len(results))
+			}
+
+			return nil
+		},
+	}
+
+	addConfigurationFlags(cmd)
+	cmd.Flags().String(bulkFile, "", "CSV file listing the agents to create")
+	cmd.Flags().String(bulkAccount, "", "Domain of the account to create the agents in (default the first account)")
+	cmd.Flags().StringP(bulkOutput, "o", "", "File to write the result of every agent to (default stdout)")
+	cmd.Flags().Duration(bulkJobTimeout, client.DefaultJobBackoff.Timeout, "How long to wait for Freshdesk to create the agents")
+	_ = cmd.MarkFlagRequired(bulkFile)
+
+	return cmd
+}
+
+// readBulkAgentsCSV reads the agents of a bulk creation. The header row names the columns, in any order.
+func readBulkAgentsCSV(r io.Reader) ([]connector.BulkAgent, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("missing header row")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(bulkColumns, column) {
+			return nil, fmt.Errorf("unknown column %q, expected %s", column, strings.Join(bulkColumns, ", "))
+		}
+		columns[column] = i
+	}
+	if _, ok := columns[bulkColumnEmail]; !ok {
+		return nil, fmt.Errorf("missing %s column", bulkColumnEmail)
+	}
+
+	var rv []connector.BulkAgent
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		rv = append(rv, connector.BulkAgent{
+			Email:       value(bulkColumnEmail),
+			Name:        value(bulkColumnName),
+			LicenseType: value(bulkColumnLicenseType),
+			TicketScope: value(bulkColumnTicketScope),
+			Roles:       splitBulkList(value(bulkColumnRoles)),
+			Groups:      splitBulkList(value(bulkColumnGroups)),
+		})
+	}
+
+	if len(rv) == 0 {
+		return nil, fmt.Errorf("no agents listed")
+	}
+
+	return rv, nil
+}
+
+func splitBulkList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ";")
+}
+
+func writeBulkResultsCSV(w io.Writer, results []connector.BulkAgentResult) error {
+	rows := [][]string{{"email", "agent_id", "success", "errors"}}
+	for _, result := range results {
+		agentID := ""
+		if result.AgentID != 0 {
+			agentID = strconv.FormatInt(result.AgentID, 10)
+		}
+
+		rows = append(rows, []string{
+			result.Email,
+			agentID,
+			strconv.FormatBool(result.Success),
+			strings.Join(result.Errors, "; "),
+		})
+	}
+
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+
+	return csvWriter.Error()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/connector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBulkAgentsCSV(t *testing.T) {
+	agents, err := readBulkAgentsCSV(strings.NewReader(
+		"Email, name, roles, groups, license_type\n" +
+			"jane@acme.com, Jane Doe, Supervisor;Tier 1, Billing, occasional\n" +
+			"joe@acme.com,,,,\n",
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []connector.BulkAgent{
+		{Email: "jane@acme.com", Name: "Jane Doe", LicenseType: "occasional", Roles: []string{"Supervisor", "Tier 1"}, Groups: []string{"Billing"}},
+		{Email: "joe@acme.com"},
+	}, agents)
+
+	_, err = readBulkAgentsCSV(strings.NewReader("name\nJane\n"))
+	require.EqualError(t, err, "missing email column")
+
+	_, err = readBulkAgentsCSV(strings.NewReader("email,phone\njane@acme.com,123\n"))
+	require.Error(t, err)
+
+	_, err = readBulkAgentsCSV(strings.NewReader("email\n"))
+	require.EqualError(t, err, "no agents listed")
+}
+
+func TestWriteBulkResultsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeBulkResultsCSV(&buf, []connector.BulkAgentResult{
+		{Email: "jane@acme.com", AgentID: 7, Success: true},
+		{Email: "joe@acme.com", Errors: []string{"email: taken", "name: missing"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "email,agent_id,success,errors\njane@acme.com,7,true,\njoe@acme.com,,false,email: taken; name: missing\n", buf.String())
+}
@@ -1,29 +1,179 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	"github.com/conductorone/baton-freshdesk/pkg/connector"
 	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/spf13/viper"
 )
 
 const (
-	apiKey = "api-key"
-	domain = "domain"
+	apiKey         = "api-key"
+	apiKeyFile     = "api-key-file"
+	apiKeyCommand  = "api-key-command"
+	domain         = "domain"
+	accounts       = "accounts"
+	grantsPageSize = "grants-page-size"
+	seatLimits     = "seat-limits"
+
+	agentDetailsConcurrency = "agent-details-concurrency"
+	syncContacts            = "sync-contacts"
+	logHTTPBodies           = "log-http-bodies"
+	vcrRecord               = "vcr-record"
+	vcrReplay               = "vcr-replay"
+
+	includeEmailDomains = "include-email-domains"
+	excludeEmailDomains = "exclude-email-domains"
+	includeAgentTypes   = "include-agent-types"
+	excludeAgentTypes   = "exclude-agent-types"
+	includeGroups       = "include-groups"
+	excludeGroups       = "exclude-groups"
+	includeRoles        = "include-roles"
+	excludeRoles        = "exclude-roles"
+
+	agentDefaultRoles  = "agent-default-roles"
+	agentDefaultGroups = "agent-default-groups"
+
+	maxAgentDetailsConcurrency = 50
 )
 
 var (
-	apiKeyField = field.StringField(apiKey, field.WithRequired(true), field.WithDescription("Freshdesk account api key"))
-	domainField = field.StringField(domain, field.WithRequired(true), field.WithDescription("Freshdesk account domain"))
+	apiKeyField     = field.StringField(apiKey, field.WithDescription("Freshdesk account api key"))
+	apiKeyFileField = field.StringField(
+		apiKeyFile,
+		field.WithDescription("File holding the Freshdesk account api key, read instead of --api-key"),
+	)
+	apiKeyCommandField = field.StringField(
+		apiKeyCommand,
+		field.WithDescription("Command printing the Freshdesk account api key, run instead of --api-key. "+
+			"Arguments are separated by spaces and no shell is involved"),
+	)
+	domainField   = field.StringField(domain, field.WithDescription("Freshdesk account domain"))
+	accountsField = field.StringSliceField(
+		accounts,
+		field.WithDescription("Additional Freshdesk accounts to sync, as domain:api-key or domain:@api-key-file pairs. "+
+			"The resource IDs of these accounts are prefixed with their domain"),
+	)
+
+	seatLimitsField = field.StringSliceField(
+		seatLimits,
+		field.WithDescription("Seats purchased per license, as category=count or domain:category=count entries. "+
+			"Categories: full_time, occasional, field_service, collaborators"),
+	)
+
+	grantsPageSizeField = field.IntField(
+		grantsPageSize,
+		field.WithDefaultValue(client.ItemsPerPage),
+		field.WithDescription("Number of agents inspected per page when listing role and group grants (1-100)"),
+	)
+	agentDetailsConcurrencyField = field.IntField(
+		agentDetailsConcurrency,
+		field.WithDefaultValue(5),
+		field.WithDescription("Maximum number of agent detail requests sent to Freshdesk in parallel (1-50)"),
+	)
+	syncContactsField = field.BoolField(
+		syncContacts,
+		field.WithDescription("Also sync the customer contacts of the accounts, who log in to the support portal, and their company memberships"),
+	)
+	logHTTPBodiesField = field.BoolField(
+		logHTTPBodies,
+		field.WithDescription("Also log the Freshdesk request and response bodies, with personal data masked, when the log level is debug"),
+	)
+	vcrRecordField = field.StringField(
+		vcrRecord,
+		field.WithDescription("Record the Freshdesk requests and responses to this cassette file. Emails are pseudonymized and phones and addresses masked, but names, job titles and descriptions are kept"),
+	)
+	vcrReplayField = field.StringField(
+		vcrReplay,
+		field.WithDescription("Replay the Freshdesk responses recorded in this cassette file instead of calling Freshdesk"),
+	)
+
+	includeEmailDomainsField = field.StringSliceField(
+		includeEmailDomains,
+		field.WithDescription("Only sync the agents whose email belongs to one of these domains"),
+	)
+	excludeEmailDomainsField = field.StringSliceField(
+		excludeEmailDomains,
+		field.WithDescription("Don't sync the agents whose email belongs to one of these domains"),
+	)
+	includeAgentTypesField = field.StringSliceField(
+		includeAgentTypes,
+		field.WithDescription("Only sync the agents of these types: support_agent, field_agent, collaborator"),
+	)
+	excludeAgentTypesField = field.StringSliceField(
+		excludeAgentTypes,
+		field.WithDescription("Don't sync the agents of these types: support_agent, field_agent, collaborator"),
+	)
+	includeGroupsField = field.StringSliceField(
+		includeGroups,
+		field.WithDescription("Only sync the groups whose name matches one of these patterns, such as Support-*"),
+	)
+	excludeGroupsField = field.StringSliceField(
+		excludeGroups,
+		field.WithDescription("Don't sync the groups whose name matches one of these patterns, such as Sandbox-*"),
+	)
+	includeRolesField = field.StringSliceField(
+		includeRoles,
+		field.WithDescription("Only sync the roles with these names"),
+	)
+	excludeRolesField = field.StringSliceField(
+		excludeRoles,
+		field.WithDescription("Don't sync the roles with these names"),
+	)
+
+	agentDefaultRolesField = field.StringSliceField(
+		agentDefaultRoles,
+		field.WithDescription("Roles, by name or ID, given to the contacts converted into agents (default the Freshdesk Agent role)"),
+	)
+	agentDefaultGroupsField = field.StringSliceField(
+		agentDefaultGroups,
+		field.WithDescription("Groups, by name or ID, the contacts converted into agents are added to"),
+	)
 
 	// ConfigurationFields defines the external configuration required for the
 	// connector to run. Note: these fields can be marked as optional or
 	// required.
-	ConfigurationFields = []field.SchemaField{apiKeyField, domainField}
+	ConfigurationFields = []field.SchemaField{
+		apiKeyField,
+		apiKeyFileField,
+		apiKeyCommandField,
+		domainField,
+		accountsField,
+		seatLimitsField,
+		grantsPageSizeField,
+		agentDetailsConcurrencyField,
+		syncContactsField,
+		logHTTPBodiesField,
+		vcrRecordField,
+		vcrReplayField,
+		includeEmailDomainsField,
+		excludeEmailDomainsField,
+		includeAgentTypesField,
+		excludeAgentTypesField,
+		includeGroupsField,
+		excludeGroupsField,
+		includeRolesField,
+		excludeRolesField,
+		agentDefaultRolesField,
+		agentDefaultGroupsField,
+	}
 
 	// FieldRelationships defines relationships between the fields listed in
 	// ConfigurationFields that can be automatically validated. For example, a
 	// username and password can be required together, or an access token can be
 	// marked as mutually exclusive from the username password pair.
-	FieldRelationships = []field.SchemaFieldRelationship{}
+	FieldRelationships = []field.SchemaFieldRelationship{
+		field.FieldsMutuallyExclusive(apiKeyField, apiKeyFileField, apiKeyCommandField),
+		field.FieldsMutuallyExclusive(vcrRecordField, vcrReplayField),
+		field.FieldsDependentOn([]field.SchemaField{apiKeyField, apiKeyFileField, apiKeyCommandField}, []field.SchemaField{domainField}),
+		field.FieldsAtLeastOneUsed(domainField, accountsField),
+	}
 )
 
 // ValidateConfig is run after the configuration is loaded, and should return an
@@ -31,5 +181,178 @@ var (
 // needs to perform extra validations that cannot be encoded with configuration
 // parameters.
 func ValidateConfig(v *viper.Viper) error {
+	_, err := accountConfigs(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = seatLimitConfigs(v)
+	if err != nil {
+		return err
+	}
+
+	syncFilters := filters(v)
+	err = syncFilters.Validate()
+	if err != nil {
+		return err
+	}
+
+	pageSize := v.GetInt(grantsPageSize)
+	if v.IsSet(grantsPageSize) && (pageSize < 1 || pageSize > client.ItemsPerPage) {
+		return fmt.Errorf("%s must be between 1 and %d, got %d", grantsPageSize, client.ItemsPerPage, pageSize)
+	}
+
+	concurrency := v.GetInt(agentDetailsConcurrency)
+	if v.IsSet(agentDetailsConcurrency) && (concurrency < 1 || concurrency > maxAgentDetailsConcurrency) {
+		return fmt.Errorf("%s must be between 1 and %d, got %d", agentDetailsConcurrency, maxAgentDetailsConcurrency, concurrency)
+	}
+
 	return nil
 }
+
+// accountConfigs returns the account given by --domain followed by every --accounts entry. The key of the
+// --domain account is given by --api-key, or read from --api-key-file or the output of --api-key-command;
+// the key of an --accounts entry is given as is, or read from the file named after an @.
+// The first account is the primary one, whose resource IDs aren't prefixed with its domain.
+func accountConfigs(v *viper.Viper) ([]connector.AccountConfig, error) {
+	var rv []connector.AccountConfig
+	if v.GetString(domain) != "" {
+		accountConfig := connector.AccountConfig{
+			Domain: v.GetString(domain),
+			APIKey: v.GetString(apiKey),
+		}
+
+		switch {
+		case v.GetString(apiKeyFile) != "":
+			accountConfig.APIKeySource = client.APIKeyFromFile(v.GetString(apiKeyFile))
+		case strings.TrimSpace(v.GetString(apiKeyCommand)) != "":
+			command := strings.Fields(v.GetString(apiKeyCommand))
+			accountConfig.APIKeySource = client.APIKeyFromCommand(command[0], command[1:]...)
+		case accountConfig.APIKey == "":
+			return nil, fmt.Errorf("%s requires one of %s, %s or %s", domain, apiKey, apiKeyFile, apiKeyCommand)
+		}
+
+		rv = append(rv, accountConfig)
+	}
+
+	for _, entry := range v.GetStringSlice(accounts) {
+		accountDomain, accountAPIKey, found := strings.Cut(entry, ":")
+		if !found || accountDomain == "" || accountAPIKey == "" {
+			return nil, fmt.Errorf("invalid %s entry %q, expected domain:api-key or domain:@api-key-file", accounts, entry)
+		}
+
+		accountConfig := connector.AccountConfig{
+			Domain: accountDomain,
+			APIKey: accountAPIKey,
+		}
+		if keyFile, found := strings.CutPrefix(accountAPIKey, "@"); found {
+			if keyFile == "" {
+				return nil, fmt.Errorf("invalid %s entry %q, the api key file is missing", accounts, entry)
+			}
+			accountConfig.APIKey = ""
+			accountConfig.APIKeySource = client.APIKeyFromFile(keyFile)
+		}
+
+		rv = append(rv, accountConfig)
+	}
+
+	seen := make(map[string]bool)
+	for _, accountConfig := range rv {
+		if seen[accountConfig.Domain] {
+			return nil, fmt.Errorf("account %s is configured more than once", accountConfig.Domain)
+		}
+		seen[accountConfig.Domain] = true
+	}
+
+	return rv, nil
+}
+
+// seatLimitConfigs parses the --seat-limits entries. Entries without a domain apply to every account.
+func seatLimitConfigs(v *viper.Viper) ([]connector.SeatLimit, error) {
+	var rv []connector.SeatLimit
+	for _, entry := range v.GetStringSlice(seatLimits) {
+		scope, count, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid %s entry %q, expected [domain:]category=count", seatLimits, entry)
+		}
+
+		var limit connector.SeatLimit
+		limit.Category = scope
+		if accountDomain, category, found := strings.Cut(scope, ":"); found {
+			limit.Domain, limit.Category = accountDomain, category
+		}
+
+		if !slices.Contains(connector.SeatCategories, limit.Category) {
+			return nil, fmt.Errorf("invalid %s entry %q, category must be one of %s", seatLimits, entry, strings.Join(connector.SeatCategories, ", "))
+		}
+
+		var err error
+		limit.Limit, err = strconv.ParseInt(count, 10, 64)
+		if err != nil || limit.Limit < 0 {
+			return nil, fmt.Errorf("invalid %s entry %q, count must be a non-negative number", seatLimits, entry)
+		}
+
+		rv = append(rv, limit)
+	}
+
+	return rv, nil
+}
+
+// openCassettes are the cassettes opened by the command being run. The SDK never closes a connector, so
+// main closes them once the command returns.
+var openCassettes []*client.Cassette
+
+// cassette opens the cassette given by --vcr-record or --vcr-replay, if any.
+func cassette(v *viper.Viper) (*client.Cassette, error) {
+	var (
+		rv  *client.Cassette
+		err error
+	)
+	switch {
+	case v.GetString(vcrRecord) != "":
+		rv, err = client.RecordCassette(v.GetString(vcrRecord))
+	case v.GetString(vcrReplay) != "":
+		rv, err = client.ReplayCassette(v.GetString(vcrReplay))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	openCassettes = append(openCassettes, rv)
+	return rv, nil
+}
+
+// closeCassettes closes the cassettes opened by the command.
+func closeCassettes() error {
+	var rv error
+	for _, c := range openCassettes {
+		rv = errors.Join(rv, c.Close())
+	}
+	openCassettes = nil
+
+	return rv
+}
+
+// filters returns the agents, groups and roles filters.
+func filters(v *viper.Viper) connector.Filters {
+	return connector.Filters{
+		IncludeEmailDomains: v.GetStringSlice(includeEmailDomains),
+		ExcludeEmailDomains: v.GetStringSlice(excludeEmailDomains),
+		IncludeAgentTypes:   v.GetStringSlice(includeAgentTypes),
+		ExcludeAgentTypes:   v.GetStringSlice(excludeAgentTypes),
+		IncludeGroups:       v.GetStringSlice(includeGroups),
+		ExcludeGroups:       v.GetStringSlice(excludeGroups),
+		IncludeRoles:        v.GetStringSlice(includeRoles),
+		ExcludeRoles:        v.GetStringSlice(excludeRoles),
+	}
+}
+
+// agentDefaults returns the roles and groups given to the contacts converted into agents.
+func agentDefaults(v *viper.Viper) connector.AgentDefaults {
+	return connector.AgentDefaults{
+		Roles:  v.GetStringSlice(agentDefaultRoles),
+		Groups: v.GetStringSlice(agentDefaultGroups),
+	}
+}
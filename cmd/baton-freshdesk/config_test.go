@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/conductorone/baton-sdk/pkg/test"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfigs(t *testing.T) {
@@ -14,8 +20,183 @@ func TestConfigs(t *testing.T) {
 	)
 
 	testCases := []test.TestCase{
-		// Add test cases here.
+		{
+			Configs: map[string]string{
+				"api-key": "key",
+				"domain":  "acme",
+			},
+			IsValid: true,
+			Message: "required fields only",
+		},
+		{
+			Configs: map[string]string{
+				"domain": "acme",
+			},
+			IsValid: false,
+			Message: "missing api key",
+		},
+		{
+			Configs: map[string]string{
+				"api-key-file": "/run/secrets/freshdesk",
+				"domain":       "acme",
+			},
+			IsValid: true,
+			Message: "api key file",
+		},
+		{
+			Configs: map[string]string{
+				"api-key-command": "vault kv get -field=api_key secret/freshdesk",
+				"domain":          "acme",
+			},
+			IsValid: true,
+			Message: "api key command",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":      "key",
+				"api-key-file": "/run/secrets/freshdesk",
+				"domain":       "acme",
+			},
+			IsValid: false,
+			Message: "api key and api key file",
+		},
+		{
+			Configs: map[string]string{
+				"api-key-file": "/run/secrets/freshdesk",
+				"accounts":     "acme-eu:key1",
+			},
+			IsValid: false,
+			Message: "api key file without domain",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":    "key",
+				"domain":     "acme",
+				"vcr-record": "sync.cassette",
+				"vcr-replay": "sync.cassette",
+			},
+			IsValid: false,
+			Message: "record and replay together",
+		},
+		{
+			Configs: map[string]string{},
+			IsValid: false,
+			Message: "no account",
+		},
+		{
+			Configs: map[string]string{
+				"accounts": "acme-eu:key1 acme-us:key2",
+			},
+			IsValid: true,
+			Message: "accounts list only",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":  "key",
+				"domain":   "acme",
+				"accounts": "acme:key2",
+			},
+			IsValid: false,
+			Message: "duplicated account",
+		},
+		{
+			Configs: map[string]string{
+				"accounts": "acme-eu",
+			},
+			IsValid: false,
+			Message: "account entry without api key",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":          "key",
+				"domain":           "acme",
+				"grants-page-size": "50",
+			},
+			IsValid: true,
+			Message: "grants page size in range",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":          "key",
+				"domain":           "acme",
+				"grants-page-size": "500",
+			},
+			IsValid: false,
+			Message: "grants page size above the Freshdesk maximum",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":                   "key",
+				"domain":                    "acme",
+				"agent-details-concurrency": "0",
+			},
+			IsValid: false,
+			Message: "agent details concurrency below one",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":     "key",
+				"domain":      "acme",
+				"seat-limits": "full_time=25 acme:occasional=5",
+			},
+			IsValid: true,
+			Message: "seat limits",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":     "key",
+				"domain":      "acme",
+				"seat-limits": "day_pass=5",
+			},
+			IsValid: false,
+			Message: "seat limit of an unknown category",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":             "key",
+				"domain":              "acme",
+				"exclude-agent-types": "collaborator",
+				"exclude-groups":      "Sandbox-*",
+			},
+			IsValid: true,
+			Message: "agent type and group filters",
+		},
+		{
+			Configs: map[string]string{
+				"api-key":             "key",
+				"domain":              "acme",
+				"include-agent-types": "contractor",
+			},
+			IsValid: false,
+			Message: "unknown agent type filter",
+		},
 	}
 
 	test.ExerciseTestCases(t, configurationSchema, ValidateConfig, testCases)
 }
+
+func TestAccountConfigs(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "acme-us.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("key2\n"), 0o600))
+
+	v := viper.New()
+	v.Set("domain", "acme")
+	v.Set("api-key", "key1")
+	v.Set("accounts", []string{"acme-us:@" + keyFile})
+
+	configs, err := accountConfigs(v)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	assert.Equal(t, "acme", configs[0].Domain)
+	assert.Equal(t, "key1", configs[0].APIKey)
+	assert.Equal(t, "acme-us", configs[1].Domain)
+	assert.Empty(t, configs[1].APIKey)
+
+	key, err := configs[1].APIKeySource(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key2", key)
+
+	v.Set("accounts", []string{"acme-us:@"})
+	_, err = accountConfigs(v)
+	require.Error(t, err)
+}
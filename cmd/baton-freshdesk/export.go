@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-freshdesk/pkg/connector"
+	"github.com/conductorone/baton-sdk/pkg/field"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	exportFormat = "format"
+	exportOutput = "output"
+	exportTable  = "table"
+
+	formatCSV  = "csv"
+	formatJSON = "json"
+
+	tableAgents      = "agents"
+	tableRoles       = "roles"
+	tableGroups      = "groups"
+	tableMemberships = "memberships"
+)
+
+// newExportCommand creates the `export` subcommand, which writes a Freshdesk access report
+// without going through a c1z file.
+func newExportCommand(ctx context.Context, v *viper.Viper) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export agents, roles, groups and their memberships as CSV or JSON",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for _, f := range ConfigurationFields {
+				err := v.BindPFlag(f.FieldName, cmd.Flags().Lookup(f.FieldName))
+				if err != nil {
+					return err
+				}
+			}
+
+			err := field.Validate(field.NewConfiguration(ConfigurationFields, FieldRelationships...), v)
+			if err != nil {
+				return err
+			}
+
+			format, _ := cmd.Flags().GetString(exportFormat)
+			table, _ := cmd.Flags().GetString(exportTable)
+			output, _ := cmd.Flags().GetString(exportOutput)
+
+			cb, err := newConnector(ctx, v)
+			if err != nil {
+				return err
+			}
+
+			snapshot, err := cb.Snapshot(ctx)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+
+			switch format {
+			case formatJSON:
+				encoder := json.NewEncoder(w)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(snapshot)
+			case formatCSV:
+				return writeSnapshotCSV(w, snapshot, table)
+			default:
+				return fmt.Errorf("unsupported export format %q, expected %s or %s", format, formatCSV, formatJSON)
+			}
+		},
+	}
+
+	addConfigurationFlags(cmd)
+	cmd.Flags().String(exportFormat, formatCSV, "Output format: csv, json")
+	cmd.Flags().StringP(exportOutput, "o", "", "File to write the report to (default stdout)")
+	cmd.Flags().String(exportTable, tableAgents, "Table written in CSV format: agents, roles, groups, memberships")
+
+	return cmd
+}
+
+// addConfigurationFlags registers the connector configuration fields as flags of cmd.
+func addConfigurationFlags(cmd *cobra.Command) {
+	for _, f := range ConfigurationFields {
+		switch f.FieldType {
+		case reflect.Bool:
+			value, _ := f.Bool()
+			cmd.Flags().Bool(f.FieldName, value, f.GetDescription())
+		case reflect.Int:
+			value, _ := f.Int()
+			cmd.Flags().Int(f.FieldName, value, f.GetDescription())
+		case reflect.Slice:
+			value, _ := f.StringSlice()
+			cmd.Flags().StringSlice(f.FieldName, value, f.GetDescription())
+		default:
+			value, _ := f.String()
+			cmd.Flags().String(f.FieldName, value, f.GetDescription())
+		}
+	}
+}
+
+func writeSnapshotCSV(w io.Writer, snapshot *connector.Snapshot, table string) error {
+	var rows [][]string
+
+	switch table {
+	case tableAgents:
+		rows = append(rows, []string{"account", "id", "name", "email", "agent_type", "roles", "groups", "ticket_scope", "license_type", "last_login_at"})
+		for _, agent := range snapshot.Agents {
+			lastLogin := ""
+			if agent.LastLoginAt != nil {
+				lastLogin = agent.LastLoginAt.Format(time.RFC3339)
+			}
+
+			rows = append(rows, []string{
+				agent.Account,
+				agent.ID,
+				agent.Name,
+				agent.Email,
+				agent.AgentType,
+				strings.Join(agent.Roles, ";"),
+				strings.Join(agent.Groups, ";"),
+				agent.TicketScope,
+				agent.LicenseType,
+				lastLogin,
+			})
+		}
+	case tableRoles, tableGroups:
+		entities := snapshot.Roles
+		if table == tableGroups {
+			entities = snapshot.Groups
+		}
+
+		rows = append(rows, []string{"account", "id", "name", "description"})
+		for _, entity := range entities {
+			rows = append(rows, []string{entity.Account, entity.ID, entity.Name, entity.Description})
+		}
+	case tableMemberships:
+		rows = append(rows, []string{"account", "agent_id", "agent_email", "resource_type", "resource_id", "resource_name"})
+		for _, membership := range snapshot.Memberships {
+			rows = append(rows, []string{
+				membership.Account,
+				membership.AgentID,
+				membership.AgentEmail,
+				membership.ResourceType,
+				membership.ResourceID,
+				membership.ResourceName,
+			})
+		}
+	default:
+		return fmt.Errorf("unsupported export table %q, expected %s, %s, %s or %s", table, tableAgents, tableRoles, tableGroups, tableMemberships)
+	}
+
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+
+	return csvWriter.Error()
+}
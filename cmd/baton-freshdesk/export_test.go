@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-freshdesk/pkg/connector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSnapshotCSV(t *testing.T) {
+	lastLogin := time.Date(2024, time.March, 1, 9, 30, 0, 0, time.UTC)
+	snapshot := &connector.Snapshot{
+		Agents: []*connector.AgentAccess{{
+			Account:     "acme",
+			ID:          "1",
+			Name:        "Jane, Doe",
+			Email:       "jane@acme.com",
+			AgentType:   "support_agent",
+			TicketScope: "global_access",
+			LicenseType: "full_time",
+			LastLoginAt: &lastLogin,
+			Roles:       []string{"Supervisor", "Agent"},
+			Groups:      []string{"Billing"},
+		}},
+		Roles: []*connector.Entity{{Account: "acme", ID: "7", Name: "Supervisor"}},
+		Memberships: []*connector.Membership{{
+			Account: "acme", AgentID: "1", AgentEmail: "jane@acme.com", ResourceType: "role", ResourceID: "7", ResourceName: "Supervisor",
+		}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, writeSnapshotCSV(&out, snapshot, tableAgents))
+	assert.Equal(t, "account,id,name,email,agent_type,roles,groups,ticket_scope,license_type,last_login_at\n"+
+		`acme,1,"Jane, Doe",jane@acme.com,support_agent,Supervisor;Agent,Billing,global_access,full_time,2024-03-01T09:30:00Z`+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, writeSnapshotCSV(&out, snapshot, tableRoles))
+	assert.Equal(t, "account,id,name,description\nacme,7,Supervisor,\n", out.String())
+
+	out.Reset()
+	require.NoError(t, writeSnapshotCSV(&out, snapshot, tableGroups))
+	assert.Equal(t, "account,id,name,description\n", out.String())
+
+	out.Reset()
+	require.NoError(t, writeSnapshotCSV(&out, snapshot, tableMemberships))
+	assert.Equal(t, "account,agent_id,agent_email,resource_type,resource_id,resource_name\nacme,1,jane@acme.com,role,7,Supervisor\n", out.String())
+
+	require.Error(t, writeSnapshotCSV(&out, snapshot, "tickets"))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/config"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/field"
+	"github.com/conductorone/baton-sdk/pkg/metrics"
 	"github.com/conductorone/baton-sdk/pkg/types"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/spf13/viper"
@@ -20,7 +22,7 @@ var version = "dev"
 func main() {
 	ctx := context.Background()
 
-	_, cmd, err := config.DefineConfiguration(
+	v, cmd, err := config.DefineConfiguration(
 		ctx,
 		"baton-freshdesk",
 		getConnector,
@@ -34,8 +36,10 @@ func main() {
 	}
 
 	cmd.Version = version
+	cmd.AddCommand(newExportCommand(ctx, v))
+	cmd.AddCommand(newBulkCreateAgentsCommand(ctx, v))
 
-	err = cmd.Execute()
+	err = errors.Join(cmd.Execute(), closeCassettes(), shutdownMeterProviders(ctx))
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
@@ -43,17 +47,9 @@ func main() {
 }
 
 func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, error) {
-	// Get params from Viper
-	fdApiKey := v.GetString(apiKey)
-	fdDomain := v.GetString(domain)
-
 	l := ctxzap.Extract(ctx)
 
-	if err := ValidateConfig(v); err != nil {
-		return nil, err
-	}
-
-	cb, err := connector.New(ctx, fdDomain, fdApiKey)
+	cb, err := newConnector(ctx, v)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
@@ -67,3 +63,45 @@ func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, e
 
 	return c, nil
 }
+
+// newConnector validates the configuration and builds the Freshdesk connector it describes.
+func newConnector(ctx context.Context, v *viper.Viper) (*connector.Connector, error) {
+	if err := ValidateConfig(v); err != nil {
+		return nil, err
+	}
+
+	// Get params from Viper
+	fdAccounts, err := accountConfigs(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fdSeatLimits, err := seatLimitConfigs(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fdCassette, err := cassette(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fdMeterProvider, err := meterProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.New(
+		ctx,
+		fdAccounts,
+		connector.WithSeatLimits(fdSeatLimits...),
+		connector.WithFilters(filters(v)),
+		connector.WithAgentDefaults(agentDefaults(v)),
+		connector.WithGrantsPageSize(v.GetInt(grantsPageSize)),
+		connector.WithAgentDetailsConcurrency(v.GetInt(agentDetailsConcurrency)),
+		connector.WithHTTPBodyLogging(v.GetBool(logHTTPBodies)),
+		connector.WithCassette(fdCassette),
+		connector.WithContactSync(v.GetBool(syncContacts)),
+		connector.WithMetricsHandler(metrics.NewOtelHandler(ctx, fdMeterProvider, "baton-freshdesk")),
+	)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// startedMeterProviders are the meter providers started by the command being run. The SDK never closes a
+// connector, so main shuts them down, exporting the metrics left, once the command returns.
+var startedMeterProviders []*sdkmetric.MeterProvider
+
+// meterProvider returns the provider the connector metrics are recorded through. When an OTLP endpoint is
+// set by OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_METRICS_ENDPOINT, the metrics are exported to it
+// over gRPC, configured by the standard OTEL_* variables, unless OTEL_METRICS_EXPORTER is none. Otherwise
+// the global provider is returned, which records nothing unless set.
+func meterProvider(ctx context.Context) (otelmetric.MeterProvider, error) {
+	endpointSet := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT") != ""
+	if !endpointSet || os.Getenv("OTEL_METRICS_EXPORTER") == "none" {
+		return otel.GetMeterProvider(), nil
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("baton-freshdesk: error creating the OTLP metrics exporter: %w", err)
+	}
+
+	rv := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+	startedMeterProviders = append(startedMeterProviders, rv)
+
+	return rv, nil
+}
+
+// shutdownMeterProviders exports the metrics left and stops the meter providers started by the command.
+func shutdownMeterProviders(ctx context.Context) error {
+	var rv error
+	for _, provider := range startedMeterProviders {
+		rv = errors.Join(rv, provider.Shutdown(ctx))
+	}
+	startedMeterProviders = nil
+
+	return rv
+}
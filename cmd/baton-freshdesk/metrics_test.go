@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestMeterProvider(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := meterProvider(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, otel.GetMeterProvider(), provider)
+	assert.Empty(t, startedMeterProviders)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:1")
+	t.Setenv("OTEL_METRICS_EXPORTER", "none")
+	provider, err = meterProvider(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, otel.GetMeterProvider(), provider)
+
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp")
+	provider, err = meterProvider(ctx)
+	require.NoError(t, err)
+	assert.IsType(t, &sdkmetric.MeterProvider{}, provider)
+	assert.Len(t, startedMeterProviders, 1)
+
+	// Nothing listens on the endpoint, so the metrics left fail to be exported on shutdown.
+	shutdownCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	assert.ErrorContains(t, shutdownMeterProviders(shutdownCtx), "failed to upload metrics")
+	assert.Empty(t, startedMeterProviders)
+}
@@ -0,0 +1,329 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// cassetteHeaders are the response headers kept in a cassette: the ones the client reads.
+var cassetteHeaders = []string{"Content-Type", "Link", "X-Ratelimit-Total", "X-Ratelimit-Remaining", "X-Ratelimit-Used-Currentrequest", "Retry-After"}
+
+var pseudonymPattern = regexp.MustCompile(`^user-[0-9a-f]{8}$`)
+
+// searchDatePattern matches the creation date bounds of a search, which a search split into date ranges
+// derives from the day it runs on.
+var searchDatePattern = regexp.MustCompile(`\bcreated_at:([<>])'\d{4}-\d{2}-\d{2}'`)
+
+// clockParams are the query parameters derived from the time of the sync.
+var clockParams = map[string]bool{
+	"_updated_since": true,
+}
+
+const (
+	normalizedTime = "[TIME]"
+	normalizedDate = "[DATE]"
+)
+
+// Interaction is one request sent to Freshdesk and the response it got, as stored in a cassette.
+type Interaction struct {
+	Domain   string              `json:"domain"`
+	Method   string              `json:"method"`
+	Path     string              `json:"path"`
+	Query    string              `json:"query,omitempty"`
+	Body     string              `json:"body,omitempty"`
+	Status   int                 `json:"status"`
+	Headers  map[string][]string `json:"headers,omitempty"`
+	Response string              `json:"response,omitempty"`
+}
+
+// Cassette records the Freshdesk requests of a client to a file, or replays a recorded file instead of
+// sending them. The file holds one Interaction per line.
+// Recordings are sanitized: the API key is never written, email addresses are replaced by a stable
+// pseudonym on the same domain and the other personal data fields are masked. Requests are sanitized the
+// same way before being matched on replay, so a replayed sync sends the very requests that were recorded.
+// Times derived from the clock, such as the start of a lookback or the date ranges of a split search, are
+// normalized so a cassette replays on any day: requests they alone told apart get their responses in the
+// recorded order.
+type Cassette struct {
+	mutex  sync.Mutex
+	file   *os.File
+	replay map[string][]Interaction
+	served map[string]int
+}
+
+// RecordCassette starts recording to path, truncating it.
+func RecordCassette(path string) (*Cassette, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("baton-freshdesk: creating the cassette: %w", err)
+	}
+
+	return &Cassette{file: file}, nil
+}
+
+// ReplayCassette loads the cassette recorded at path. Replayed clients never reach the network: a request
+// missing from the cassette fails.
+func ReplayCassette(path string) (*Cassette, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("baton-freshdesk: opening the cassette: %w", err)
+	}
+	defer file.Close()
+
+	c := &Cassette{
+		replay: make(map[string][]Interaction),
+		served: make(map[string]int),
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var interaction Interaction
+		err := json.Unmarshal(scanner.Bytes(), &interaction)
+		if err != nil {
+			return nil, fmt.Errorf("baton-freshdesk: reading line %d of the cassette: %w", line, err)
+		}
+
+		key := interaction.key()
+		c.replay[key] = append(c.replay[key], interaction)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("baton-freshdesk: reading the cassette: %w", err)
+	}
+
+	return c, nil
+}
+
+// Close closes the file being recorded to.
+func (c *Cassette) Close() error {
+	if c.file == nil {
+		return nil
+	}
+
+	return c.file.Close()
+}
+
+// WithCassette records the requests of the client to the cassette, or replays them from it.
+func WithCassette(cassette *Cassette) Option {
+	return func(c *FreshdeskClient) {
+		c.cassette = cassette
+	}
+}
+
+// transport returns the transport recording to the cassette over next, or replaying from it.
+func (c *Cassette) transport(domain string, next http.RoundTripper) http.RoundTripper {
+	if c.replay != nil {
+		return &replayTransport{cassette: c, domain: domain}
+	}
+
+	return &recordTransport{cassette: c, domain: domain, next: next}
+}
+
+func (i *Interaction) key() string {
+	return strings.Join([]string{i.Domain, i.Method, i.Path, i.Query, i.Body}, "\n")
+}
+
+// newInteraction returns the sanitized request of an interaction. The request body is read and restored.
+func newInteraction(domain string, req *http.Request) (*Interaction, error) {
+	interaction := &Interaction{
+		Domain: domain,
+		Method: req.Method,
+		Path:   pseudonymizeText(req.URL.Path),
+	}
+
+	query := req.URL.Query()
+	for key, values := range query {
+		for i := range values {
+			switch {
+			case clockParams[key]:
+				values[i] = normalizedTime
+			case piiFields[key] && key != "email":
+				values[i] = redacted
+			default:
+				values[i] = searchDatePattern.ReplaceAllString(pseudonymizeText(values[i]), "created_at:$1'"+normalizedDate+"'")
+			}
+		}
+	}
+	interaction.Query = query.Encode()
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		if len(body) > 0 && string(body) != "null" {
+			interaction.Body = sanitizeBody(body)
+		}
+	}
+
+	return interaction, nil
+}
+
+type recordTransport struct {
+	cassette *Cassette
+	domain   string
+	next     http.RoundTripper
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	interaction, err := newInteraction(t.domain, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction.Status = resp.StatusCode
+	interaction.Headers = make(map[string][]string)
+	for _, header := range cassetteHeaders {
+		if values := resp.Header.Values(header); len(values) > 0 {
+			interaction.Headers[header] = values
+		}
+	}
+	if len(body) > 0 {
+		interaction.Response = sanitizeBody(body)
+	}
+
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return nil, err
+	}
+
+	t.cassette.mutex.Lock()
+	defer t.cassette.mutex.Unlock()
+
+	_, err = t.cassette.file.Write(append(line, '\n'))
+	if err != nil {
+		return nil, fmt.Errorf("baton-freshdesk: recording to the cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+type replayTransport struct {
+	cassette *Cassette
+	domain   string
+}
+
+// RoundTrip serves the recorded response of a request. A request recorded several times, such as a
+// polled job, gets its responses in the recorded order, then the last one again.
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	interaction, err := newInteraction(t.domain, req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := interaction.key()
+
+	t.cassette.mutex.Lock()
+	recorded := t.cassette.replay[key]
+	served := t.cassette.served[key]
+	if served < len(recorded)-1 {
+		t.cassette.served[key]++
+	}
+	t.cassette.mutex.Unlock()
+
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("baton-freshdesk: %s %s?%s of %s isn't in the cassette", interaction.Method, interaction.Path, interaction.Query, t.domain)
+	}
+	match := recorded[served]
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Status, http.StatusText(match.Status)),
+		StatusCode:    match.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(strings.NewReader(match.Response)),
+		ContentLength: int64(len(match.Response)),
+		Request:       req,
+	}
+	for header, values := range match.Headers {
+		for _, value := range values {
+			resp.Header.Add(header, value)
+		}
+	}
+
+	return resp, nil
+}
+
+// sanitizeBody pseudonymizes the email addresses of a body and, in JSON bodies, masks the personal data fields.
+func sanitizeBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return pseudonymizeText(string(body))
+	}
+
+	sanitized, err := json.Marshal(sanitizeJSON(value))
+	if err != nil {
+		return pseudonymizeText(string(body))
+	}
+
+	return string(sanitized)
+}
+
+func sanitizeJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if piiFields[key] && key != "email" && key != "other_emails" && field != nil {
+				v[key] = redacted
+				continue
+			}
+			v[key] = sanitizeJSON(field)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = sanitizeJSON(v[i])
+		}
+		return v
+	case string:
+		return pseudonymizeText(v)
+	default:
+		return v
+	}
+}
+
+// pseudonymizeText replaces the local part of every email address with a pseudonym derived from the
+// whole address, so the same person keeps the same address across the cassette. Pseudonyms are left as is.
+func pseudonymizeText(text string) string {
+	return emailPattern.ReplaceAllStringFunc(text, func(email string) string {
+		local, domain, _ := strings.Cut(email, "@")
+		if pseudonymPattern.MatchString(local) {
+			return email
+		}
+
+		sum := sha256.Sum256([]byte(strings.ToLower(email)))
+		return "user-" + hex.EncodeToString(sum[:4]) + "@" + domain
+	})
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassetteRecordsAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Remaining", "99")
+		_, _ = w.Write([]byte(`[{"id":7,"contact":{"name":"Jane","email":"jane@acme.com","mobile":"+1 555 0100"}}]`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "sync.cassette")
+	recorder, err := RecordCassette(path)
+	require.NoError(t, err)
+
+	c, err := New(context.Background(), WithBaseURL(server.URL), WithBearerToken("secret-key"), WithCassette(recorder))
+	require.NoError(t, err)
+
+	recorded, err := c.ListAllAgents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	require.NoError(t, recorder.Close())
+	server.Close()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "secret-key")
+	assert.NotContains(t, string(content), "jane@acme.com")
+	assert.NotContains(t, string(content), "555")
+	assert.Contains(t, string(content), "@acme.com")
+
+	player, err := ReplayCassette(path)
+	require.NoError(t, err)
+
+	c, err = New(context.Background(), WithBaseURL(server.URL), WithBearerToken("other-key"), WithCassette(player))
+	require.NoError(t, err)
+
+	replayed, err := c.ListAllAgents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+	assert.Equal(t, int64(7), replayed[0].ID)
+	assert.Equal(t, "Jane", replayed[0].Contact.Name)
+	assert.Equal(t, pseudonymizeText("jane@acme.com"), replayed[0].Contact.Email)
+	remaining, ok := c.RateLimitRemaining()
+	assert.True(t, ok)
+	assert.Equal(t, int64(99), remaining)
+
+	_, _, err = c.GetAccount(context.Background())
+	require.Error(t, err)
+}
+
+func TestCassetteReplaysOnAnotherDay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/contacts":
+			_, _ = w.Write([]byte(`[{"id":2,"name":"John"}]`))
+		case "/api/v2/search/contacts":
+			_, _ = w.Write([]byte(`{"results":[{"id":3,"name":"Joe"}],"total":1}`))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "sync.cassette")
+	recorder, err := RecordCassette(path)
+	require.NoError(t, err)
+
+	recordedAt := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	sync := func(c *FreshdeskClient, now time.Time) {
+		contacts, _, _, err := c.ListContacts(context.Background(), PageOptions{}, WithUpdatedSince(now.AddDate(0, 0, -7)))
+		require.NoError(t, err)
+		require.Len(t, contacts, 1)
+		assert.Equal(t, int64(2), contacts[0].ID)
+
+		contacts, err = c.SearchContacts(context.Background(), SearchAnd(SearchEq("company_id", 1), SearchLTE("created_at", now)))
+		require.NoError(t, err)
+		require.Len(t, contacts, 1)
+		assert.Equal(t, int64(3), contacts[0].ID)
+	}
+
+	c, err := New(context.Background(), WithBaseURL(server.URL), WithBearerToken("secret-key"), WithCassette(recorder))
+	require.NoError(t, err)
+	sync(c, recordedAt)
+	require.NoError(t, recorder.Close())
+	server.Close()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "2024-0")
+
+	player, err := ReplayCassette(path)
+	require.NoError(t, err)
+
+	c, err = New(context.Background(), WithBaseURL(server.URL), WithBearerToken("other-key"), WithCassette(player))
+	require.NoError(t, err)
+	sync(c, recordedAt.AddDate(0, 0, 3))
+}
+
+func TestPseudonymizeTextIsStable(t *testing.T) {
+	pseudonym := pseudonymizeText("Contact jane@acme.com")
+	assert.Regexp(t, `^Contact user-[0-9a-f]{8}@acme\.com$`, pseudonym)
+	assert.Equal(t, pseudonym, pseudonymizeText("Contact jane@acme.com"))
+	assert.Equal(t, pseudonym, pseudonymizeText(pseudonym))
+}
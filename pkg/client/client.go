@@ -3,16 +3,20 @@ package client
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/metrics"
+	"github.com/conductorone/baton-sdk/pkg/ratelimit"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
-	"github.com/tomnomnom/linkheader"
+	"google.golang.org/grpc/codes"
 )
 
 // Endpoints available for Freshdesk APIs.
@@ -20,21 +24,67 @@ const (
 	baseURL = "https://.freshdesk.com"
 
 	// GET endpoints.
-	allAgents = "/api/v2/agents"
-	allGrous  = "/api/v2/groups"
-	allRoles  = "/api/v2/roles"
+	accountDetail = "/api/v2/account"
+	allAgents     = "/api/v2/agents"
+	allGrous      = "/api/v2/groups"
+	allRoles      = "/api/v2/roles"
+
+	allContacts           = "/api/v2/contacts"
+	allCompanies          = "/api/v2/companies"
+	allContactSegments    = "/api/v2/segments/contact_filters"
+	allSolutionCategories = "/api/v2/solutions/categories"
+	solutionFolders       = "/api/v2/solutions/categories/%d/folders" // Must indicate the category ID.
+	getSolutionFolder     = "/api/v2/solutions/folders"               // Must indicate the folder ID: /[id].
+	allProducts           = "/api/v2/products"
+	allPortals            = "/api/v2/portals"
+	allEmailConfigs       = "/api/v2/email_configs"
+	getEmailConfig        = "/api/v2/email_configs" // Must indicate the email config ID: /[id].
+
+	allCannedResponseFolders = "/api/v2/canned_response_folders"
+	cannedResponses          = "/api/v2/canned_response_folders/%d/responses" // Must indicate the folder ID.
+	allTicketTemplates       = "/api/v2/ticket_templates"
+	getTicketTemplate        = "/api/v2/ticket_templates" // Must indicate the template ID: /[id].
+
+	allScenarioAutomations = "/api/v2/scenario_automations"
+	automationRules        = "/api/v2/automations/%d/rules" // Must indicate the automation type.
+
+	allBusinessHours = "/api/v2/business_hours"
+	allSlaPolicies   = "/api/v2/sla_policies"
 
 	getAgentDetail = "/api/v2/agents" // Must indicate the agent ID: /[id].
 
+	searchContacts = "/api/v2/search/contacts"
+
+	getJob = "/api/v2/jobs" // Must indicate the job ID: /[id].
+
+	// POST endpoints.
+	createAgent      = "/api/v2/agents"
+	createAgentsBulk = "/api/v2/agents/bulk"
+
 	// PUT endpoints.
-	updateAgent = "/api/v2/agents" // Must indicate the agent ID: /[id].
+	updateAgent       = "/api/v2/agents"                  // Must indicate the agent ID: /[id].
+	sendContactInvite = "/api/v2/contacts/%d/send_invite" // Must indicate the contact ID.
+	makeAgent         = "/api/v2/contacts/%d/make_agent"  // Must indicate the contact ID.
+
+	// DELETE endpoints.
+	deleteAgent = "/api/v2/agents" // Must indicate the agent ID: /[id].
 )
 
 type FreshdeskClient struct {
 	httpClient   *uhttp.BaseHttpClient
 	freshdeskURL string
+	customURL    string
 	domain       string
 	token        string
+	tokenSource  APIKeySource
+	logBodies    bool
+	cassette     *Cassette
+
+	metricsHandler metrics.Handler
+	metrics        *clientMetrics
+
+	// rateLimitRemaining holds the last X-Ratelimit-Remaining value seen, or -1 before any response.
+	rateLimitRemaining atomic.Int64
 }
 
 type Option func(client *FreshdeskClient)
@@ -46,27 +96,63 @@ func New(ctx context.Context, opts ...Option) (*FreshdeskClient, error) {
 		domain:       "",
 		token:        "",
 	}
+	freshdeskClient.rateLimitRemaining.Store(-1)
 
 	for _, o := range opts {
 		o(freshdeskClient)
 	}
 
-	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
+	if freshdeskClient.metricsHandler == nil {
+		freshdeskClient.metricsHandler = metrics.NewNoOpHandler(ctx)
+	}
+	freshdeskClient.metrics = newClientMetrics(freshdeskClient.metricsHandler.WithTags(map[string]string{
+		"domain": freshdeskClient.domain,
+	}))
+
+	if freshdeskClient.tokenSource != nil {
+		token, err := freshdeskClient.tokenSource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		freshdeskClient.token = token
+	}
+
+	logger := redactLogger(ctxzap.Extract(ctx), freshdeskClient.logSecrets()...)
+	// Requests are logged by loggingTransport, which masks credentials and personal data, rather than by uhttp.
+	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(false, logger))
 	if err != nil {
 		return nil, err
 	}
+	transport := httpClient.Transport
+	if freshdeskClient.cassette != nil {
+		transport = freshdeskClient.cassette.transport(freshdeskClient.domain, transport)
+	}
+	// Metrics are recorded below the uhttp response cache, so only the requests sent are counted.
+	transport = &metricsTransport{
+		next:               transport,
+		metrics:            freshdeskClient.metrics,
+		rateLimitRemaining: &freshdeskClient.rateLimitRemaining,
+	}
+	httpClient.Transport = &loggingTransport{
+		next:      transport,
+		logger:    logger,
+		logBodies: freshdeskClient.logBodies,
+	}
 
 	cli, err := uhttp.NewBaseHttpClientWithContext(context.Background(), httpClient)
 	if err != nil {
 		return nil, err
 	}
 
-	dotIndex := strings.Index(baseURL, ".")
-	if dotIndex == -1 {
-		return nil, fmt.Errorf("invalid URL: %s", baseURL)
-	}
+	fdURL := freshdeskClient.customURL
+	if fdURL == "" {
+		dotIndex := strings.Index(baseURL, ".")
+		if dotIndex == -1 {
+			return nil, fmt.Errorf("invalid URL: %s", baseURL)
+		}
 
-	fdURL := baseURL[:dotIndex] + freshdeskClient.domain + baseURL[dotIndex:]
+		fdURL = baseURL[:dotIndex] + freshdeskClient.domain + baseURL[dotIndex:]
+	}
 	if !isValidUrl(fdURL) {
 		return nil, fmt.Errorf("the URL: %s is not valid", fdURL)
 	}
@@ -83,6 +169,21 @@ func WithBearerToken(apiToken string) Option {
 	}
 }
 
+// WithBodyLogging also logs the request and response bodies, with their personal data masked, when
+// debug logging is on. Meant for troubleshooting.
+func WithBodyLogging(enabled bool) Option {
+	return func(c *FreshdeskClient) {
+		c.logBodies = enabled
+	}
+}
+
+// WithBaseURL overrides the https://[domain].freshdesk.com URL derived from the domain.
+func WithBaseURL(baseURL string) Option {
+	return func(c *FreshdeskClient) {
+		c.customURL = baseURL
+	}
+}
+
 func WithDomain(domain string) Option {
 	return func(c *FreshdeskClient) {
 		c.domain = domain
@@ -93,29 +194,70 @@ func (f *FreshdeskClient) getToken() string {
 	return f.token
 }
 
+// logSecrets returns what must never be logged: the API key, as is and in the Basic Authorization header.
+func (f *FreshdeskClient) logSecrets() []string {
+	if f.token == "" {
+		return nil
+	}
+
+	return []string{f.token, basicAuth(f.token, "X")}
+}
+
 func (f *FreshdeskClient) GetDomain() string {
 	return f.domain
 }
 
+// RateLimitRemaining returns how many requests Freshdesk still allows in the current window,
+// as reported by the last response. The boolean is false until a response carried the header.
+func (f *FreshdeskClient) RateLimitRemaining() (int64, bool) {
+	remaining := f.rateLimitRemaining.Load()
+	return remaining, remaining >= 0
+}
+
 func isValidUrl(urlBase string) bool {
 	u, err := url.Parse(urlBase)
 	return err == nil && u.Scheme != "" && u.Host != ""
 }
 
 // ListAgents Gets all the Agents from Freshdesk and deserialized them into an Array of Agents.
-func (f *FreshdeskClient) ListAgents(ctx context.Context, opts PageOptions) ([]Agent, string, annotations.Annotations, error) {
-	queryUrl, err := url.JoinPath(f.freshdeskURL, allAgents)
+// The agents can be filtered server-side with WithAgentEmail, WithAgentMobile, WithAgentPhone and WithAgentState.
+func (f *FreshdeskClient) ListAgents(ctx context.Context, opts PageOptions, filters ...ReqOpt) ([]Agent, string, annotations.Annotations, error) {
+	return listPage[Agent](ctx, f, allAgents, opts, filters...)
+}
+
+// GetAgentByEmail looks an agent up by email. It returns nil when no agent has that email.
+func (f *FreshdeskClient) GetAgentByEmail(ctx context.Context, email string) (*Agent, annotations.Annotations, error) {
+	agents, _, anno, err := f.ListAgents(ctx, PageOptions{PerPage: 1}, WithAgentEmail(email))
 	if err != nil {
-		return nil, "", nil, err
+		return nil, nil, err
+	}
+
+	if len(agents) == 0 {
+		return nil, anno, nil
 	}
 
-	var res []Agent
-	nextPage, annotation, err := f.getListFromAPI(ctx, queryUrl, &res, WithPage(opts.Page), WithPageLimit(opts.PerPage))
+	return &agents[0], anno, nil
+}
+
+// ListAllAgents reads every agent of the account.
+func (f *FreshdeskClient) ListAllAgents(ctx context.Context) ([]Agent, error) {
+	return Paginate[Agent](f, allAgents, "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+// GetAccount Gets the details of the Freshdesk account: plan, data center and agent seats in use.
+func (f *FreshdeskClient) GetAccount(ctx context.Context) (*Account, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, accountDetail)
 	if err != nil {
-		return nil, "", nil, err
+		return nil, nil, err
 	}
 
-	return res, nextPage, annotation, nil
+	var res *Account
+	_, annotation, err := f.doRequest(ctx, http.MethodGet, queryUrl, &res, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, annotation, nil
 }
 
 // GetAgentDetail Gets all the Agents from Freshdesk and deserialized them into an Array of Agents.
@@ -133,32 +275,37 @@ func (f *FreshdeskClient) GetAgentDetail(ctx context.Context, agentID string) (*
 	return res, annotation, nil
 }
 
-// getListFromAPI sends a request to the Freshdesk API to receive a JSON with a list of entities.
-func (f *FreshdeskClient) getListFromAPI(
-	ctx context.Context,
-	urlAddress string,
-	res any,
-	reqOpt ...ReqOpt,
-) (string, annotations.Annotations, error) {
-	header, annotation, err := f.doRequest(ctx, http.MethodGet, urlAddress, &res, nil, reqOpt...)
-	if err != nil {
-		return "", nil, err
-	}
-
-	var pageToken string
-	pagingLinks := linkheader.Parse(header.Get("Link"))
-	for _, link := range pagingLinks {
-		if link.Rel == "next" {
-			nextPageUrl, err := url.Parse(link.URL)
-			if err != nil {
-				return "", nil, err
-			}
-			pageToken = nextPageUrl.Query().Get("page")
-			break
-		}
+// listPage reads a single page of a list endpoint and returns the cursor for the next one.
+func listPage[T any](ctx context.Context, f *FreshdeskClient, path string, opts PageOptions, reqOpts ...ReqOpt) ([]T, string, annotations.Annotations, error) {
+	reqOpts = append([]ReqOpt{WithPageLimit(opts.PerPage)}, reqOpts...)
+	paginator := Paginate[T](f, path, opts.Cursor, reqOpts...)
+
+	res, annotation, err := paginator.NextPage(ctx)
+	if err != nil {
+		return nil, "", nil, err
 	}
 
-	return pageToken, annotation, nil
+	return res, paginator.Cursor(), annotation, nil
+}
+
+// checkSameHost makes sure the credentials are only ever sent to the configured Freshdesk host,
+// whatever URL a next page link or a resumed cursor points to.
+func (f *FreshdeskClient) checkSameHost(urlAddress string) error {
+	base, err := url.Parse(f.freshdeskURL)
+	if err != nil {
+		return err
+	}
+
+	target, err := url.Parse(urlAddress)
+	if err != nil {
+		return err
+	}
+
+	if target.Host != base.Host {
+		return fmt.Errorf("refusing to send request to %s: expected host %s", target.Host, base.Host)
+	}
+
+	return nil
 }
 
 func (f *FreshdeskClient) doRequest(
@@ -177,6 +324,7 @@ func (f *FreshdeskClient) doRequest(
 	if err != nil {
 		return nil, nil, err
 	}
+
 	for _, o := range reqOptions {
 		o(urlAddress)
 	}
@@ -196,6 +344,14 @@ func (f *FreshdeskClient) doRequest(
 
 	switch method {
 	case http.MethodGet, http.MethodPut, http.MethodPost:
+		if method == http.MethodGet && ctx.Value(noCacheKey{}) != nil {
+			resp, err = f.doUncached(req, res)
+			if resp != nil {
+				defer resp.Body.Close()
+			}
+			break
+		}
+
 		doOptions := []uhttp.DoOption{}
 		if res != nil {
 			doOptions = append(doOptions, uhttp.WithResponse(&res))
@@ -211,48 +367,218 @@ func (f *FreshdeskClient) doRequest(
 		}
 	}
 
-	if err != nil {
-		return nil, nil, err
+	annotation := annotations.Annotations{}
+	if resp != nil {
+		rateLimitData, rlErr := ratelimit.ExtractRateLimitData(resp.StatusCode, &resp.Header)
+		if rlErr == nil && rateLimitData != nil {
+			annotation.WithRateLimiting(rateLimitData)
+		}
 	}
 
-	annotation := annotations.Annotations{}
+	if err != nil {
+		return nil, annotation, err
+	}
 
 	return resp.Header, annotation, nil
 }
 
+type noCacheKey struct{}
+
+// withoutCache makes the GET requests sent with ctx skip the uhttp response cache, for resources
+// polled until they change, such as background jobs.
+func withoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// doUncached sends req straight through the underlying HTTP client, bypassing the response cache
+// of uhttp.BaseHttpClient, and decodes the JSON response into res.
+func (f *FreshdeskClient) doUncached(req *http.Request, res interface{}) (*http.Response, error) {
+	resp, err := f.httpClient.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return resp, uhttp.WrapErrorsWithRateLimitInfo(codes.Unavailable, resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		return resp, uhttp.WrapErrorsWithRateLimitInfo(codes.Unknown, resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+	}
+
+	if res != nil {
+		err = json.NewDecoder(resp.Body).Decode(res)
+		if err != nil {
+			return resp, fmt.Errorf("failed to decode response body: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
 func basicAuth(username, password string) string {
 	auth := username + ":" + password
 	return base64.StdEncoding.EncodeToString([]byte(auth))
 }
 
-func (f *FreshdeskClient) ListRoles(ctx context.Context, opts PageOptions) (*[]Role, string, annotations.Annotations, error) {
-	queryUrl, err := url.JoinPath(f.freshdeskURL, allRoles)
+func (f *FreshdeskClient) ListRoles(ctx context.Context, opts PageOptions) ([]Role, string, annotations.Annotations, error) {
+	return listPage[Role](ctx, f, allRoles, opts)
+}
+
+// ListAllRoles reads every role of the account.
+func (f *FreshdeskClient) ListAllRoles(ctx context.Context) ([]Role, error) {
+	return Paginate[Role](f, allRoles, "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+func (f *FreshdeskClient) ListGroups(ctx context.Context, opts PageOptions) ([]Group, string, annotations.Annotations, error) {
+	return listPage[Group](ctx, f, allGrous, opts)
+}
+
+// ListContacts Gets the customer contacts of the account. Deleted contacts are only listed when filtered on with
+// WithContactState; the list can also be narrowed with WithUpdatedSince.
+func (f *FreshdeskClient) ListContacts(ctx context.Context, opts PageOptions, filters ...ReqOpt) ([]Contact, string, annotations.Annotations, error) {
+	return listPage[Contact](ctx, f, allContacts, opts, filters...)
+}
+
+func (f *FreshdeskClient) ListCompanies(ctx context.Context, opts PageOptions) ([]Company, string, annotations.Annotations, error) {
+	return listPage[Company](ctx, f, allCompanies, opts)
+}
+
+func (f *FreshdeskClient) ListContactSegments(ctx context.Context, opts PageOptions) ([]ContactSegment, string, annotations.Annotations, error) {
+	return listPage[ContactSegment](ctx, f, allContactSegments, opts)
+}
+
+func (f *FreshdeskClient) ListSolutionCategories(ctx context.Context, opts PageOptions) ([]SolutionCategory, string, annotations.Annotations, error) {
+	return listPage[SolutionCategory](ctx, f, allSolutionCategories, opts)
+}
+
+// ListSolutionFolders lists the top-level folders of a knowledge-base category.
+func (f *FreshdeskClient) ListSolutionFolders(ctx context.Context, categoryID int64, opts PageOptions) ([]SolutionFolder, string, annotations.Annotations, error) {
+	return listPage[SolutionFolder](ctx, f, fmt.Sprintf(solutionFolders, categoryID), opts)
+}
+
+// GetSolutionFolder Gets a knowledge-base folder along with the companies and segments it is visible to.
+func (f *FreshdeskClient) GetSolutionFolder(ctx context.Context, folderID int64) (*SolutionFolder, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, getSolutionFolder, "/", strconv.FormatInt(folderID, 10))
 	if err != nil {
-		return nil, "", nil, err
+		return nil, nil, err
 	}
 
-	var res *[]Role
-	nextPage, annotation, err := f.getListFromAPI(ctx, queryUrl, &res, WithPage(opts.Page), WithPageLimit(opts.PerPage))
+	var res *SolutionFolder
+	_, anno, err := f.doRequest(ctx, http.MethodGet, queryUrl, &res, nil)
 	if err != nil {
-		return nil, "", nil, err
+		return nil, nil, err
 	}
 
-	return res, nextPage, annotation, nil
+	return res, anno, nil
+}
+
+func (f *FreshdeskClient) ListProducts(ctx context.Context, opts PageOptions) ([]Product, string, annotations.Annotations, error) {
+	return listPage[Product](ctx, f, allProducts, opts)
+}
+
+// ListAllPortals reads every support portal of the account.
+func (f *FreshdeskClient) ListAllPortals(ctx context.Context) ([]Portal, error) {
+	return Paginate[Portal](f, allPortals, "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+func (f *FreshdeskClient) ListEmailConfigs(ctx context.Context, opts PageOptions) ([]EmailConfig, string, annotations.Annotations, error) {
+	return listPage[EmailConfig](ctx, f, allEmailConfigs, opts)
 }
 
-func (f *FreshdeskClient) ListGroups(ctx context.Context, opts PageOptions) (*[]Group, string, annotations.Annotations, error) {
-	queryUrl, err := url.JoinPath(f.freshdeskURL, allGrous)
+// ListAllEmailConfigs reads every support mailbox of the account.
+func (f *FreshdeskClient) ListAllEmailConfigs(ctx context.Context) ([]EmailConfig, error) {
+	return Paginate[EmailConfig](f, allEmailConfigs, "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+// GetEmailConfig Gets a support mailbox along with the group its tickets are routed to.
+func (f *FreshdeskClient) GetEmailConfig(ctx context.Context, emailConfigID int64) (*EmailConfig, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, getEmailConfig, "/", strconv.FormatInt(emailConfigID, 10))
 	if err != nil {
-		return nil, "", nil, err
+		return nil, nil, err
 	}
 
-	var res *[]Group
-	nextPage, annotation, err := f.getListFromAPI(ctx, queryUrl, &res, WithPage(opts.Page), WithPageLimit(opts.PerPage))
+	var res *EmailConfig
+	_, anno, err := f.doRequest(ctx, http.MethodGet, queryUrl, &res, nil)
 	if err != nil {
-		return nil, "", nil, err
+		return nil, nil, err
 	}
 
-	return res, nextPage, annotation, nil
+	return res, anno, nil
+}
+
+func (f *FreshdeskClient) ListCannedResponseFolders(ctx context.Context, opts PageOptions) ([]CannedResponseFolder, string, annotations.Annotations, error) {
+	return listPage[CannedResponseFolder](ctx, f, allCannedResponseFolders, opts)
+}
+
+// ListAllCannedResponses reads every canned response of a folder.
+func (f *FreshdeskClient) ListAllCannedResponses(ctx context.Context, folderID int64) ([]CannedResponse, error) {
+	return Paginate[CannedResponse](f, fmt.Sprintf(cannedResponses, folderID), "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+func (f *FreshdeskClient) ListTicketTemplates(ctx context.Context, opts PageOptions) ([]TicketTemplate, string, annotations.Annotations, error) {
+	return listPage[TicketTemplate](ctx, f, allTicketTemplates, opts)
+}
+
+// GetTicketTemplate Gets a ticket template along with the agents and groups it is shared with.
+func (f *FreshdeskClient) GetTicketTemplate(ctx context.Context, templateID int64) (*TicketTemplate, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, getTicketTemplate, "/", strconv.FormatInt(templateID, 10))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res *TicketTemplate
+	_, anno, err := f.doRequest(ctx, http.MethodGet, queryUrl, &res, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, anno, nil
+}
+
+func (f *FreshdeskClient) ListScenarioAutomations(ctx context.Context, opts PageOptions) ([]ScenarioAutomation, string, annotations.Annotations, error) {
+	return listPage[ScenarioAutomation](ctx, f, allScenarioAutomations, opts)
+}
+
+// ListAllScenarioAutomations reads every scenario automation of the account.
+func (f *FreshdeskClient) ListAllScenarioAutomations(ctx context.Context) ([]ScenarioAutomation, error) {
+	return Paginate[ScenarioAutomation](f, allScenarioAutomations, "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+// ListAllAutomationRules reads every rule of an automation type (ticket creation, time triggers or ticket updates).
+func (f *FreshdeskClient) ListAllAutomationRules(ctx context.Context, automationType int64) ([]AutomationRule, error) {
+	rules, err := Paginate[AutomationRule](f, fmt.Sprintf(automationRules, automationType), "", WithPageLimit(ItemsPerPage)).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		rules[i].AutomationType = automationType
+	}
+
+	return rules, nil
+}
+
+// ListAllGroups reads every group of the account.
+func (f *FreshdeskClient) ListAllGroups(ctx context.Context) ([]Group, error) {
+	return Paginate[Group](f, allGrous, "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+func (f *FreshdeskClient) ListBusinessHours(ctx context.Context, opts PageOptions) ([]BusinessHour, string, annotations.Annotations, error) {
+	return listPage[BusinessHour](ctx, f, allBusinessHours, opts)
+}
+
+// ListAllBusinessHours reads every business hours calendar of the account.
+func (f *FreshdeskClient) ListAllBusinessHours(ctx context.Context) ([]BusinessHour, error) {
+	return Paginate[BusinessHour](f, allBusinessHours, "", WithPageLimit(ItemsPerPage)).All(ctx)
+}
+
+func (f *FreshdeskClient) ListSlaPolicies(ctx context.Context, opts PageOptions) ([]SlaPolicy, string, annotations.Annotations, error) {
+	return listPage[SlaPolicy](ctx, f, allSlaPolicies, opts)
+}
+
+// ListAllSlaPolicies reads every SLA policy of the account.
+func (f *FreshdeskClient) ListAllSlaPolicies(ctx context.Context) ([]SlaPolicy, error) {
+	return Paginate[SlaPolicy](f, allSlaPolicies, "", WithPageLimit(ItemsPerPage)).All(ctx)
 }
 
 func (f *FreshdeskClient) UpdateAgent(ctx context.Context, agent *Agent) (annotations.Annotations, error) {
@@ -273,3 +599,85 @@ func (f *FreshdeskClient) UpdateAgent(ctx context.Context, agent *Agent) (annota
 
 	return anno, nil
 }
+
+// UpdateAgentOccasional switches an agent between a full-time and an occasional (day pass) license.
+func (f *FreshdeskClient) UpdateAgentOccasional(ctx context.Context, agentID int64, occasional bool) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, updateAgent, "/", strconv.FormatInt(agentID, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"occasional": occasional,
+	}
+
+	_, anno, err := f.doRequest(ctx, http.MethodPut, queryUrl, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return anno, nil
+}
+
+// CreateAgent creates an agent. Freshdesk sends the activation email to the new agent.
+func (f *FreshdeskClient) CreateAgent(ctx context.Context, agent *CreateAgentRequest) (*Agent, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, createAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res *Agent
+	_, anno, err := f.doRequest(ctx, http.MethodPost, queryUrl, &res, agent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, anno, nil
+}
+
+// SendContactInvite (re)sends the activation email that lets a contact set a password and log in to the support portal.
+func (f *FreshdeskClient) SendContactInvite(ctx context.Context, contactID int64) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, fmt.Sprintf(sendContactInvite, contactID))
+	if err != nil {
+		return nil, err
+	}
+
+	// The endpoint takes no parameters, but Freshdesk expects a JSON body on PUT requests.
+	_, anno, err := f.doRequest(ctx, http.MethodPut, queryUrl, nil, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	return anno, nil
+}
+
+// MakeAgent converts a contact into an agent. The agent keeps the ID of the contact.
+func (f *FreshdeskClient) MakeAgent(ctx context.Context, contactID int64, agent *MakeAgentRequest) (*Agent, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, fmt.Sprintf(makeAgent, contactID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res *Agent
+	_, anno, err := f.doRequest(ctx, http.MethodPut, queryUrl, &res, agent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, anno, nil
+}
+
+// DeleteAgent downgrades an agent to a contact, which keeps the ID of the agent. It frees the agent's seat.
+func (f *FreshdeskClient) DeleteAgent(ctx context.Context, agentID int64) (annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, deleteAgent, "/", strconv.FormatInt(agentID, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	_, anno, err := f.doRequest(ctx, http.MethodDelete, queryUrl, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return anno, nil
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAgentsSendsFilters(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "occasional", r.URL.Query().Get("state"))
+		assert.Equal(t, "+1 555", r.URL.Query().Get("phone"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":1,"occasional":true}]`))
+	})
+
+	agents, _, _, err := c.ListAgents(context.Background(), PageOptions{}, WithAgentState(AgentStateOccasional), WithAgentPhone("+1 555"))
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.True(t, agents[0].Occasional)
+}
+
+func TestGetAgentByEmail(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("email") == "jane@acme.com" {
+			_, _ = w.Write([]byte(`[{"id":7,"contact":{"email":"jane@acme.com"}}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	agent, _, err := c.GetAgentByEmail(context.Background(), "jane@acme.com")
+	require.NoError(t, err)
+	require.NotNil(t, agent)
+	assert.Equal(t, int64(7), agent.ID)
+
+	agent, _, err = c.GetAgentByEmail(context.Background(), "joe@acme.com")
+	require.NoError(t, err)
+	assert.Nil(t, agent)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// apiKeyCommandTimeout bounds how long an API key command may run.
+const apiKeyCommandTimeout = 30 * time.Second
+
+// APIKeySource returns the API key of an account. It is called every time a client is created,
+// so a key rotated in its file or secret manager is picked up by the next client.
+type APIKeySource func(ctx context.Context) (string, error)
+
+// APIKeyFromFile reads the API key from a file, ignoring the surrounding whitespace.
+func APIKeyFromFile(path string) APIKeySource {
+	return func(_ context.Context) (string, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("baton-freshdesk: reading the api key file: %w", err)
+		}
+
+		key := strings.TrimSpace(string(content))
+		if key == "" {
+			return "", fmt.Errorf("baton-freshdesk: the api key file %s is empty", path)
+		}
+
+		return key, nil
+	}
+}
+
+// APIKeyFromCommand runs a command, such as a secret manager CLI, and reads the API key from its output.
+// The command runs without a shell; its standard error is reported when it fails.
+func APIKeyFromCommand(name string, args ...string) APIKeySource {
+	return func(ctx context.Context) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+		defer cancel()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		if err != nil {
+			return "", fmt.Errorf("baton-freshdesk: running the api key command %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+
+		key := strings.TrimSpace(stdout.String())
+		if key == "" {
+			return "", fmt.Errorf("baton-freshdesk: the api key command %s printed nothing", name)
+		}
+
+		return key, nil
+	}
+}
+
+// WithAPIKeySource reads the API key from source when the client is created, instead of WithBearerToken.
+func WithAPIKeySource(source APIKeySource) Option {
+	return func(c *FreshdeskClient) {
+		c.tokenSource = source
+	}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAPIKeyFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-key")
+	require.NoError(t, os.WriteFile(path, []byte("secret-key\n"), 0o600))
+
+	key, err := APIKeyFromFile(path)(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "secret-key", key)
+
+	require.NoError(t, os.WriteFile(path, []byte(" \n"), 0o600))
+	_, err = APIKeyFromFile(path)(context.Background())
+	require.Error(t, err)
+}
+
+func TestAPIKeyFromCommand(t *testing.T) {
+	key, err := APIKeyFromCommand("echo", "secret-key")(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "secret-key", key)
+
+	_, err = APIKeyFromCommand("false")(context.Background())
+	require.Error(t, err)
+}
+
+func TestNewReloadsTheAPIKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-key")
+	require.NoError(t, os.WriteFile(path, []byte("first-key"), 0o600))
+
+	c, err := New(context.Background(), WithDomain("acme"), WithAPIKeySource(APIKeyFromFile(path)))
+	require.NoError(t, err)
+	assert.Equal(t, "first-key", c.getToken())
+
+	require.NoError(t, os.WriteFile(path, []byte("second-key"), 0o600))
+	c, err = New(context.Background(), WithDomain("acme"), WithAPIKeySource(APIKeyFromFile(path)))
+	require.NoError(t, err)
+	assert.Equal(t, "second-key", c.getToken())
+}
+
+func TestRedactSecrets(t *testing.T) {
+	var out bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.AddSync(&out), zap.DebugLevel)
+	logger := redactLogger(zap.New(core), "secret-key", basicAuth("secret-key", "X"))
+
+	logger.With(zap.String("token", "secret-key")).Debug("sent Basic "+basicAuth("secret-key", "X"),
+		zap.Error(fmt.Errorf("request with secret-key for jane@acme.com failed")),
+		zap.String("header", "Authorization: Basic "+basicAuth("secret-key", "X")),
+	)
+
+	assert.NotContains(t, out.String(), "secret-key")
+	assert.NotContains(t, out.String(), basicAuth("secret-key", "X"))
+	assert.Contains(t, out.String(), "sent Basic "+redacted)
+	assert.Contains(t, out.String(), "request with "+redacted+" for "+redacted+" failed")
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+)
+
+// Done reports whether the job stopped running, whether it succeeded or not.
+func (j *Job) Done() bool {
+	switch strings.ToUpper(strings.ReplaceAll(j.Status, "_", " ")) {
+	case "", JobStatusQueued, JobStatusInProgress:
+		return false
+	default:
+		return true
+	}
+}
+
+// ErrorMessages returns the errors of a failed record. Freshdesk reports them either as a list of
+// field errors or as a free-form value, which is returned as is.
+func (r *JobRecord) ErrorMessages() []string {
+	if len(r.Errors) == 0 || string(r.Errors) == "null" {
+		return nil
+	}
+
+	var fieldErrors []JobError
+	if err := json.Unmarshal(r.Errors, &fieldErrors); err == nil {
+		var rv []string
+		for _, fieldError := range fieldErrors {
+			if fieldError.Field == "" {
+				rv = append(rv, fieldError.Message)
+				continue
+			}
+			rv = append(rv, fieldError.Field+": "+fieldError.Message)
+		}
+		return rv
+	}
+
+	var message string
+	if err := json.Unmarshal(r.Errors, &message); err == nil {
+		return []string{message}
+	}
+
+	return []string{string(r.Errors)}
+}
+
+// JobBackoff is how often WaitForJob polls a job: it waits Initial after the first poll and doubles
+// the wait up to Max. It gives up after Timeout, unless Timeout is zero.
+type JobBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Timeout time.Duration
+}
+
+// DefaultJobBackoff suits bulk operations of a few hundred records.
+var DefaultJobBackoff = JobBackoff{
+	Initial: time.Second,
+	Max:     30 * time.Second,
+	Timeout: 15 * time.Minute,
+}
+
+// CreateAgents creates agents in the background. Freshdesk sends the activation email to every new agent;
+// the result of each agent is read from the returned job.
+func (f *FreshdeskClient) CreateAgents(ctx context.Context, agents []CreateAgentRequest) (*JobReference, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, createAgentsBulk)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := map[string]interface{}{
+		"agents": agents,
+	}
+
+	var res *JobReference
+	_, anno, err := f.doRequest(ctx, http.MethodPost, queryUrl, &res, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res == nil || res.JobID == "" {
+		return nil, anno, fmt.Errorf("baton-freshdesk: bulk agent creation didn't return a job")
+	}
+
+	return res, anno, nil
+}
+
+// GetJob Gets the status of a background job and, once done, the result of its records.
+// The job is never read from the response cache, as its status changes between polls.
+func (f *FreshdeskClient) GetJob(ctx context.Context, jobID string) (*Job, annotations.Annotations, error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, getJob, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res *Job
+	_, anno, err := f.doRequest(withoutCache(ctx), http.MethodGet, queryUrl, &res, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res == nil {
+		return nil, anno, fmt.Errorf("baton-freshdesk: job %s not found", jobID)
+	}
+
+	return res, anno, nil
+}
+
+// WaitForJob polls a job with backoff until it is done, the timeout elapses or the context is cancelled.
+func (f *FreshdeskClient) WaitForJob(ctx context.Context, jobID string, backoff JobBackoff) (*Job, error) {
+	if backoff.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, backoff.Timeout)
+		defer cancel()
+	}
+
+	delay := backoff.Initial
+	if delay <= 0 {
+		delay = DefaultJobBackoff.Initial
+	}
+	maxDelay := max(backoff.Max, delay)
+
+	for {
+		job, _, err := f.GetJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+
+		if job.Done() {
+			return job, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("baton-freshdesk: job %s still %s: %w", jobID, job.Status, ctx.Err())
+		case <-timer.C:
+		}
+
+		delay = min(delay*2, maxDelay)
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForJobPollsUntilDone(t *testing.T) {
+	polls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/jobs/job-1", r.URL.Path)
+		polls++
+
+		w.Header().Set("Content-Type", "application/json")
+		if polls < 3 {
+			_, _ = w.Write([]byte(`{"id":"job-1","status":"IN PROGRESS"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"job-1","status":"COMPLETED","data":[
+			{"id":7,"email":"jane@acme.com","success":true},
+			{"email":"joe@acme.com","success":false,"errors":[{"field":"email","message":"It should be a unique value"}]}
+		]}`))
+	})
+
+	job, err := c.WaitForJob(context.Background(), "job-1", JobBackoff{Initial: time.Millisecond, Max: 2 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, 3, polls)
+	require.Len(t, job.Data, 2)
+	assert.True(t, job.Data[0].Success)
+	assert.Nil(t, job.Data[0].ErrorMessages())
+	assert.Equal(t, []string{"email: It should be a unique value"}, job.Data[1].ErrorMessages())
+}
+
+func TestWaitForJobTimesOut(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"job-1","status":"QUEUED"}`))
+	})
+
+	_, err := c.WaitForJob(context.Background(), "job-1", JobBackoff{Initial: time.Millisecond, Timeout: 20 * time.Millisecond})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
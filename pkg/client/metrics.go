@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/metrics"
+)
+
+var idSegmentPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// clientMetrics are the Freshdesk API usage metrics of a client. Requests are tagged by endpoint, method
+// and status; the endpoint is the path with its IDs replaced by :id, so every agent shares one series.
+type clientMetrics struct {
+	requests           metrics.Int64Counter
+	requestDuration    metrics.Int64Histogram
+	rateLimitRemaining metrics.Int64Gauge
+}
+
+// WithMetricsHandler reports the API usage of the client through handler, tagged with the account domain.
+func WithMetricsHandler(handler metrics.Handler) Option {
+	return func(c *FreshdeskClient) {
+		c.metricsHandler = handler
+	}
+}
+
+func newClientMetrics(handler metrics.Handler) *clientMetrics {
+	return &clientMetrics{
+		requests: handler.Int64Counter(
+			"freshdesk_api_requests",
+			"Number of requests sent to the Freshdesk API",
+			metrics.Dimensionless,
+		),
+		requestDuration: handler.Int64Histogram(
+			"freshdesk_api_request_duration",
+			"Duration of the requests sent to the Freshdesk API",
+			metrics.Milliseconds,
+		),
+		rateLimitRemaining: handler.Int64Gauge(
+			"freshdesk_api_ratelimit_remaining",
+			"Requests the Freshdesk API still allows in the current rate limit window",
+			metrics.Dimensionless,
+		),
+	}
+}
+
+// recordRequest records a request sent to path. status is zero when no response was received.
+func (m *clientMetrics) recordRequest(ctx context.Context, method, path string, status int, duration time.Duration) {
+	statusTag := "error"
+	if status != 0 {
+		statusTag = strconv.Itoa(status)
+	}
+
+	tags := map[string]string{
+		"endpoint": endpointName(path),
+		"method":   method,
+		"status":   statusTag,
+	}
+
+	m.requests.Add(ctx, 1, tags)
+	m.requestDuration.Record(ctx, duration.Milliseconds(), tags)
+}
+
+// metricsTransport records the requests sent to Freshdesk and the rate limit their responses report.
+// It sits under the uhttp response cache, so cached responses are neither counted nor observed again.
+type metricsTransport struct {
+	next               http.RoundTripper
+	metrics            *clientMetrics
+	rateLimitRemaining *atomic.Int64
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	t.metrics.recordRequest(req.Context(), req.Method, req.URL.Path, status, time.Since(start))
+
+	if err == nil {
+		remaining, parseErr := strconv.ParseInt(resp.Header.Get("X-Ratelimit-Remaining"), 10, 64)
+		if parseErr == nil {
+			t.rateLimitRemaining.Store(remaining)
+			t.metrics.rateLimitRemaining.Observe(req.Context(), remaining, nil)
+		}
+	}
+
+	return resp, err
+}
+
+// endpointName replaces the IDs of a path with :id, e.g. /api/v2/agents/42 becomes /api/v2/agents/:id.
+// Job IDs aren't numeric, so the segment after /jobs/ is always an ID.
+func endpointName(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idSegmentPattern.MatchString(segment) || (i > 0 && segments[i-1] == "jobs" && segment != "") {
+			segments[i] = ":id"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
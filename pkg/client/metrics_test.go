@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/conductorone/baton-sdk/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type recordedMetric struct {
+	name  string
+	value int64
+	tags  map[string]string
+}
+
+// testMetricsHandler keeps every value recorded, with the handler tags merged into the value tags.
+type testMetricsHandler struct {
+	mutex    *sync.Mutex
+	recorded *[]recordedMetric
+	tags     map[string]string
+}
+
+type testRecorder struct {
+	handler *testMetricsHandler
+	name    string
+}
+
+func (r *testRecorder) record(value int64, tags map[string]string) {
+	merged := map[string]string{}
+	for k, v := range r.handler.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+
+	r.handler.mutex.Lock()
+	defer r.handler.mutex.Unlock()
+	*r.handler.recorded = append(*r.handler.recorded, recordedMetric{name: r.name, value: value, tags: merged})
+}
+
+func (r *testRecorder) Add(_ context.Context, value int64, tags map[string]string) {
+	r.record(value, tags)
+}
+
+func (r *testRecorder) Record(_ context.Context, value int64, tags map[string]string) {
+	r.record(value, tags)
+}
+
+func (r *testRecorder) Observe(_ context.Context, value int64, tags map[string]string) {
+	r.record(value, tags)
+}
+
+func (h *testMetricsHandler) Int64Counter(name string, _ string, _ metrics.Unit) metrics.Int64Counter {
+	return &testRecorder{handler: h, name: name}
+}
+
+func (h *testMetricsHandler) Int64Gauge(name string, _ string, _ metrics.Unit) metrics.Int64Gauge {
+	return &testRecorder{handler: h, name: name}
+}
+
+func (h *testMetricsHandler) Int64Histogram(name string, _ string, _ metrics.Unit) metrics.Int64Histogram {
+	return &testRecorder{handler: h, name: name}
+}
+
+func (h *testMetricsHandler) WithTags(tags map[string]string) metrics.Handler {
+	return &testMetricsHandler{mutex: h.mutex, recorded: h.recorded, tags: tags}
+}
+
+func TestRequestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Remaining", "17")
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var recorded []recordedMetric
+	handler := &testMetricsHandler{mutex: &sync.Mutex{}, recorded: &recorded}
+
+	c, err := New(context.Background(), WithBaseURL(server.URL), WithDomain("acme"), WithBearerToken("token"), WithMetricsHandler(handler))
+	require.NoError(t, err)
+
+	_, _, err = c.GetAgentDetail(context.Background(), "42")
+	require.NoError(t, err)
+
+	byName := make(map[string]recordedMetric)
+	for _, metric := range recorded {
+		byName[metric.name] = metric
+	}
+
+	requests := byName["freshdesk_api_requests"]
+	assert.Equal(t, int64(1), requests.value)
+	assert.Equal(t, map[string]string{"domain": "acme", "endpoint": "/api/v2/agents/:id", "method": "GET", "status": "200"}, requests.tags)
+	assert.Contains(t, byName, "freshdesk_api_request_duration")
+	assert.Equal(t, int64(17), byName["freshdesk_api_ratelimit_remaining"].value)
+
+	// A response served from the uhttp cache was not sent, so it records nothing.
+	count := len(recorded)
+	_, _, err = c.GetAgentDetail(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Len(t, recorded, count)
+}
+
+func TestRequestMetricsAreExportedThroughOtel(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Remaining", "17")
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	handler := metrics.NewOtelHandler(ctx, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), "baton-freshdesk")
+
+	c, err := New(ctx, WithBaseURL(server.URL), WithDomain("acme"), WithBearerToken("token"), WithMetricsHandler(handler))
+	require.NoError(t, err)
+
+	for range 2 {
+		_, _, err = c.GetAgentDetail(ctx, "42")
+		require.NoError(t, err)
+	}
+
+	var collected metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &collected))
+
+	byName := make(map[string]metricdata.Aggregation)
+	for _, scope := range collected.ScopeMetrics {
+		for _, metric := range scope.Metrics {
+			byName[metric.Name] = metric.Data
+		}
+	}
+
+	requests, ok := byName["freshdesk_api_requests"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, requests.DataPoints, 1)
+	assert.Equal(t, int64(2), requests.DataPoints[0].Value)
+	assert.Equal(t, attribute.NewSet(
+		attribute.String("domain", "acme"),
+		attribute.String("endpoint", "/api/v2/agents/:id"),
+		attribute.String("method", "GET"),
+		attribute.String("status", "200"),
+	), requests.DataPoints[0].Attributes)
+
+	durations, ok := byName["freshdesk_api_request_duration"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, durations.DataPoints, 1)
+	assert.Equal(t, uint64(2), durations.DataPoints[0].Count)
+
+	remaining, ok := byName["freshdesk_api_ratelimit_remaining"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, remaining.DataPoints, 1)
+	assert.Equal(t, int64(17), remaining.DataPoints[0].Value)
+}
+
+func TestEndpointName(t *testing.T) {
+	assert.Equal(t, "/api/v2/agents", endpointName("/api/v2/agents"))
+	assert.Equal(t, "/api/v2/contacts/:id/make_agent", endpointName("/api/v2/contacts/42/make_agent"))
+	assert.Equal(t, "/api/v2/jobs/:id", endpointName("/api/v2/jobs/c4b1-9e2f"))
+}
@@ -1,6 +1,9 @@
 package client
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Agent struct {
 	ID             int64     `json:"id,omitempty"`
@@ -17,20 +20,46 @@ type Agent struct {
 	UpdatedAt      time.Time `json:"updated_at,omitempty"`
 	Contact        Contact   `json:"contact,omitempty"`
 	FocusMode      bool      `json:"focus_mode,omitempty"`
+	Deactivated    bool      `json:"deactivated,omitempty"`
 }
 
+type CreateAgentRequest struct {
+	Email       string  `json:"email"`
+	Name        string  `json:"name,omitempty"`
+	TicketScope int64   `json:"ticket_scope"`
+	Occasional  bool    `json:"occasional"`
+	RoleIDs     []int64 `json:"role_ids,omitempty"`
+	GroupIDs    []int64 `json:"group_ids,omitempty"`
+}
+
+// MakeAgentRequest holds the agent settings of a contact converted into an agent.
+type MakeAgentRequest struct {
+	TicketScope int64   `json:"ticket_scope"`
+	Occasional  bool    `json:"occasional"`
+	RoleIDs     []int64 `json:"role_ids,omitempty"`
+	GroupIDs    []int64 `json:"group_ids,omitempty"`
+}
+
+// Contact is both a customer contact and the contact record nested in an Agent.
+// ID, CompanyID and CustomFields are only set on customer contacts. Deleted contacts are soft-deleted
+// and can still be restored; blocked contacts can't log in or open tickets.
 type Contact struct {
-	Active      bool      `json:"active,omitempty"`
-	Email       string    `json:"email,omitempty"`
-	JobTitle    string    `json:"job_title,omitempty"`
-	Language    string    `json:"language,omitempty"`
-	LastLoginAt time.Time `json:"last_login_at,omitempty"`
-	Mobile      string    `json:"mobile,omitempty"`
-	Name        string    `json:"name,omitempty"`
-	Phone       string    `json:"phone,omitempty"`
-	TimeZone    string    `json:"time_zone,omitempty"`
-	CreatedAt   time.Time `json:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	ID           int64                  `json:"id,omitempty"`
+	Active       bool                   `json:"active,omitempty"`
+	Email        string                 `json:"email,omitempty"`
+	JobTitle     string                 `json:"job_title,omitempty"`
+	Language     string                 `json:"language,omitempty"`
+	LastLoginAt  time.Time              `json:"last_login_at,omitempty"`
+	Mobile       string                 `json:"mobile,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Phone        string                 `json:"phone,omitempty"`
+	TimeZone     string                 `json:"time_zone,omitempty"`
+	CompanyID    int64                  `json:"company_id,omitempty"`
+	Deleted      bool                   `json:"deleted,omitempty"`
+	Blocked      bool                   `json:"blocked,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	CreatedAt    time.Time              `json:"created_at,omitempty"`
+	UpdatedAt    time.Time              `json:"updated_at,omitempty"`
 }
 
 type Role struct {
@@ -38,10 +67,18 @@ type Role struct {
 	Description string    `json:"description,omitempty"`
 	Name        string    `json:"name,omitempty"`
 	Default     bool      `json:"default,omitempty"`
+	AgentType   int64     `json:"agent_type,omitempty"`
 	CreatedAt   time.Time `json:"created_at,omitempty"`
 	UpdatedAt   time.Time `json:"updated_at,omitempty"`
 }
 
+// Agent types of a Role.
+const (
+	RoleAgentTypeSupport      = 1
+	RoleAgentTypeFieldService = 2
+	RoleAgentTypeCollaborator = 3
+)
+
 type Group struct {
 	ID               int64     `json:"id,omitempty"`
 	AgentIDs         []int64   `json:"agent_ids,omitempty"`
@@ -54,3 +91,261 @@ type Group struct {
 	CreatedAt        time.Time `json:"created_at,omitempty"`
 	UpdatedAt        time.Time `json:"updated_at,omitempty"`
 }
+
+type Account struct {
+	AccountID      int64         `json:"account_id,omitempty"`
+	AccountName    string        `json:"account_name,omitempty"`
+	AccountDomain  string        `json:"account_domain,omitempty"`
+	TierType       string        `json:"tier_type,omitempty"`
+	Timezone       string        `json:"timezone,omitempty"`
+	DataCenter     string        `json:"data_center,omitempty"`
+	TotalAgents    TotalAgents   `json:"total_agents,omitempty"`
+	ContactPerson  ContactPerson `json:"contact_person,omitempty"`
+	OrganisationID int64         `json:"organisation_id,omitempty"`
+}
+
+type TotalAgents struct {
+	FullTime      int64 `json:"full_time,omitempty"`
+	Occasional    int64 `json:"occasional,omitempty"`
+	FieldService  int64 `json:"field_service,omitempty"`
+	Collaborators int64 `json:"collaborators,omitempty"`
+}
+
+type ContactPerson struct {
+	FirstName string `json:"firstname,omitempty"`
+	LastName  string `json:"lastname,omitempty"`
+	Email     string `json:"email,omitempty"`
+}
+
+type Company struct {
+	ID           int64                  `json:"id,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Domains      []string               `json:"domains,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	CreatedAt    time.Time              `json:"created_at,omitempty"`
+	UpdatedAt    time.Time              `json:"updated_at,omitempty"`
+}
+
+type ContactSegment struct {
+	ID        int64     `json:"id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+type SolutionCategory struct {
+	ID               int64     `json:"id,omitempty"`
+	Name             string    `json:"name,omitempty"`
+	Description      string    `json:"description,omitempty"`
+	VisibleInPortals []int64   `json:"visible_in_portals,omitempty"`
+	CreatedAt        time.Time `json:"created_at,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at,omitempty"`
+}
+
+// Visibility values of a SolutionFolder.
+const (
+	FolderVisibilityAllUsers        = 1
+	FolderVisibilityLoggedInUsers   = 2
+	FolderVisibilityAgents          = 3
+	FolderVisibilityCompanies       = 4
+	FolderVisibilityBots            = 5
+	FolderVisibilityContactSegments = 6
+	FolderVisibilityCompanySegments = 7
+)
+
+type SolutionFolder struct {
+	ID                int64     `json:"id,omitempty"`
+	Name              string    `json:"name,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	Visibility        int64     `json:"visibility,omitempty"`
+	CompanyIDs        []int64   `json:"company_ids,omitempty"`
+	ContactSegmentIDs []int64   `json:"contact_segment_ids,omitempty"`
+	CompanySegmentIDs []int64   `json:"company_segment_ids,omitempty"`
+	ParentFolderID    int64     `json:"parent_folder_id,omitempty"`
+	ArticlesCount     int64     `json:"articles_count,omitempty"`
+	SubFoldersCount   int64     `json:"sub_folders_count,omitempty"`
+	CreatedAt         time.Time `json:"created_at,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at,omitempty"`
+}
+
+type Product struct {
+	ID           int64     `json:"id,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	PrimaryEmail string    `json:"primary_email,omitempty"`
+	Default      bool      `json:"default,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
+
+type Portal struct {
+	ID        int64     `json:"id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	PortalURL string    `json:"portal_url,omitempty"`
+	ProductID int64     `json:"product_id,omitempty"`
+	Default   bool      `json:"default,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+type EmailConfig struct {
+	ID          int64     `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	ToEmail     string    `json:"to_email,omitempty"`
+	ReplyEmail  string    `json:"reply_email,omitempty"`
+	GroupID     int64     `json:"group_id,omitempty"`
+	ProductID   int64     `json:"product_id,omitempty"`
+	PrimaryRole bool      `json:"primary_role,omitempty"`
+	Active      bool      `json:"active,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// Visibility values of the canned responses, ticket templates and scenario automations agents share.
+const (
+	VisibilityAllAgents = 0
+	VisibilityPersonal  = 1
+	VisibilityGroups    = 2
+)
+
+type CannedResponseFolder struct {
+	ID             int64     `json:"id,omitempty"`
+	Name           string    `json:"name,omitempty"`
+	Personal       bool      `json:"personal,omitempty"`
+	ResponsesCount int64     `json:"responses_count,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+type CannedResponse struct {
+	ID         int64     `json:"id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	FolderID   int64     `json:"folder_id,omitempty"`
+	Visibility int64     `json:"visibility"`
+	GroupIDs   []int64   `json:"group_ids,omitempty"`
+	UserID     int64     `json:"user_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+type TicketTemplate struct {
+	ID          int64     `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Visibility  int64     `json:"visibility"`
+	GroupIDs    []int64   `json:"group_ids,omitempty"`
+	UserID      int64     `json:"user_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// ScenarioAutomation is a set of ticket actions agents run in one click. Visibility tells whether every
+// agent, the listed agents or the members of the listed groups can run it.
+type ScenarioAutomation struct {
+	ID          int64     `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Visibility  int64     `json:"visibility"`
+	AgentIDs    []int64   `json:"agent_ids,omitempty"`
+	GroupIDs    []int64   `json:"group_ids,omitempty"`
+	CreatedBy   int64     `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// Automation types of the automation rules endpoint.
+const (
+	AutomationTypeTicketCreation = 1
+	AutomationTypeTimeTriggers   = 3
+	AutomationTypeTicketUpdates  = 4
+)
+
+// AutomationTypes lists every automation type rules can be read for.
+var AutomationTypes = []int64{AutomationTypeTicketCreation, AutomationTypeTimeTriggers, AutomationTypeTicketUpdates}
+
+type AutomationRule struct {
+	ID            int64     `json:"id,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Position      int64     `json:"position,omitempty"`
+	Active        bool      `json:"active,omitempty"`
+	Summary       string    `json:"summary,omitempty"`
+	CreatedBy     int64     `json:"created_by,omitempty"`
+	LastUpdatedBy int64     `json:"last_updated_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+
+	// AutomationType is the automation type the rule was read from. It isn't part of the rule itself.
+	AutomationType int64 `json:"-"`
+}
+
+type BusinessHour struct {
+	ID          int64     `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	IsDefault   bool      `json:"is_default,omitempty"`
+	TimeZone    string    `json:"time_zone,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+type SlaPolicy struct {
+	ID           int64           `json:"id,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	Description  string          `json:"description,omitempty"`
+	Active       bool            `json:"active,omitempty"`
+	IsDefault    bool            `json:"is_default,omitempty"`
+	Position     int64           `json:"position,omitempty"`
+	ApplicableTo SlaApplicableTo `json:"applicable_to,omitempty"`
+	CreatedAt    time.Time       `json:"created_at,omitempty"`
+	UpdatedAt    time.Time       `json:"updated_at,omitempty"`
+}
+
+// SlaApplicableTo holds the conditions a ticket must meet for an SLA policy to apply.
+type SlaApplicableTo struct {
+	GroupIDs    []int64  `json:"group_ids,omitempty"`
+	CompanyIDs  []int64  `json:"company_ids,omitempty"`
+	ProductIDs  []int64  `json:"product_ids,omitempty"`
+	Sources     []int64  `json:"sources,omitempty"`
+	TicketTypes []string `json:"ticket_types,omitempty"`
+}
+
+// Statuses of a Job. Freshdesk reports them in upper case, with a space in IN PROGRESS.
+const (
+	JobStatusQueued     = "QUEUED"
+	JobStatusInProgress = "IN PROGRESS"
+	JobStatusCompleted  = "COMPLETED"
+	JobStatusFailed     = "FAILED"
+)
+
+// JobReference is the answer of an endpoint that runs in the background: the job to poll for the result.
+type JobReference struct {
+	JobID string `json:"job_id,omitempty"`
+	Href  string `json:"href,omitempty"`
+}
+
+// Job is a background job, such as a bulk agent creation, along with the result of every record once done.
+type Job struct {
+	ID              string      `json:"id,omitempty"`
+	Status          string      `json:"status,omitempty"`
+	Progress        int64       `json:"progress,omitempty"`
+	Data            []JobRecord `json:"data,omitempty"`
+	CreatedAt       time.Time   `json:"created_at,omitempty"`
+	StatusUpdatedAt time.Time   `json:"status_updated_at,omitempty"`
+}
+
+// JobRecord is the result of one record of a job. Freshdesk identifies the record by the ID
+// it was given, or by the email of the agent for bulk agent creation.
+type JobRecord struct {
+	ID      int64           `json:"id,omitempty"`
+	Email   string          `json:"email,omitempty"`
+	Success bool            `json:"success"`
+	Errors  json.RawMessage `json:"errors,omitempty"`
+}
+
+// JobError is a validation error of a job record.
+type JobError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
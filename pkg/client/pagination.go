@@ -1,8 +1,15 @@
 package client
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/tomnomnom/linkheader"
 )
 
 // By default, the number of objects returned per page is 30.
@@ -16,7 +23,8 @@ const ItemsPerPage = 100
 // It's used to create query string.
 type PageOptions struct {
 	PerPage int `url:"limit,omitempty"`
-	Page    int `url:"page,omitempty"`
+	// Cursor is the next page URL returned by a previous list call. Empty means the first page.
+	Cursor string `url:"-"`
 }
 
 type ReqOpt func(reqURL *url.URL)
@@ -45,3 +53,193 @@ func WithQueryParam(key string, value string) ReqOpt {
 		reqURL.RawQuery = q.Encode()
 	}
 }
+
+// AgentState is the license state the agents list can be filtered on.
+type AgentState string
+
+const (
+	AgentStateFullTime   AgentState = "fulltime"
+	AgentStateOccasional AgentState = "occasional"
+)
+
+// WithAgentEmail : Only list the agent with this email.
+func WithAgentEmail(email string) ReqOpt {
+	return WithQueryParam("email", email)
+}
+
+// WithAgentMobile : Only list the agents with this mobile number.
+func WithAgentMobile(mobile string) ReqOpt {
+	return WithQueryParam("mobile", mobile)
+}
+
+// WithAgentPhone : Only list the agents with this phone number.
+func WithAgentPhone(phone string) ReqOpt {
+	return WithQueryParam("phone", phone)
+}
+
+// WithAgentState : Only list the full-time or the occasional agents.
+func WithAgentState(state AgentState) ReqOpt {
+	return WithQueryParam("state", string(state))
+}
+
+// ContactState is the state the contacts list can be filtered on.
+type ContactState string
+
+const (
+	ContactStateBlocked    ContactState = "blocked"
+	ContactStateDeleted    ContactState = "deleted"
+	ContactStateUnverified ContactState = "unverified"
+	ContactStateVerified   ContactState = "verified"
+)
+
+// WithContactState : Only list the contacts in this state.
+func WithContactState(state ContactState) ReqOpt {
+	return WithQueryParam("state", string(state))
+}
+
+// WithUpdatedSince : Only list the contacts updated at or after this time.
+func WithUpdatedSince(since time.Time) ReqOpt {
+	return WithQueryParam("_updated_since", since.UTC().Format(time.RFC3339))
+}
+
+// Paginator walks a Freshdesk list endpoint page by page, following the URL of the
+// `Link: <...>; rel="next"` response header exactly as Freshdesk sends it.
+// It can either hand out whole pages (NextPage) or stream single items (Next/Item).
+type Paginator[T any] struct {
+	client  *FreshdeskClient
+	nextURL string
+	reqOpts []ReqOpt
+	started bool
+
+	items   []T
+	current T
+	err     error
+}
+
+// Paginate creates a Paginator for the endpoint path. When cursor holds a next page URL
+// from a previous Paginator, listing resumes from it and reqOpts are ignored, as the URL
+// already carries every query parameter.
+func Paginate[T any](f *FreshdeskClient, path string, cursor string, reqOpts ...ReqOpt) *Paginator[T] {
+	p := &Paginator[T]{
+		client:  f,
+		reqOpts: reqOpts,
+	}
+
+	if cursor != "" {
+		p.nextURL = cursor
+		p.reqOpts = nil
+		return p
+	}
+
+	p.nextURL, p.err = url.JoinPath(f.freshdeskURL, path)
+
+	return p
+}
+
+// HasNext reports whether another page can be requested.
+func (p *Paginator[T]) HasNext() bool {
+	return p.err == nil && (!p.started || p.nextURL != "")
+}
+
+// Cursor returns the URL of the next page, or an empty string once the last page was read.
+func (p *Paginator[T]) Cursor() string {
+	return p.nextURL
+}
+
+// NextPage requests the next page of items.
+func (p *Paginator[T]) NextPage(ctx context.Context) ([]T, annotations.Annotations, error) {
+	if p.err != nil {
+		return nil, nil, p.err
+	}
+	if !p.HasNext() {
+		return nil, nil, nil
+	}
+
+	pageURL := p.nextURL
+	err := p.client.checkSameHost(pageURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res []T
+	header, annotation, err := p.client.doRequest(ctx, http.MethodGet, pageURL, &res, nil, p.reqOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nextURL, err := nextLink(pageURL, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.started = true
+	p.reqOpts = nil
+	p.nextURL = nextURL
+
+	return res, annotation, nil
+}
+
+// Next advances to the next item, fetching further pages as needed. It returns false
+// when every page was read, the context is done, or a request failed; check Err afterwards.
+func (p *Paginator[T]) Next(ctx context.Context) bool {
+	for len(p.items) == 0 {
+		if p.err != nil || !p.HasNext() {
+			return false
+		}
+
+		if err := ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		p.items, _, p.err = p.NextPage(ctx)
+	}
+
+	p.current = p.items[0]
+	p.items = p.items[1:]
+
+	return true
+}
+
+// Item returns the item Next advanced to.
+func (p *Paginator[T]) Item() T {
+	return p.current
+}
+
+// Err returns the error that stopped Next, if any.
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// All reads every remaining item.
+func (p *Paginator[T]) All(ctx context.Context) ([]T, error) {
+	var rv []T
+	for p.Next(ctx) {
+		rv = append(rv, p.Item())
+	}
+
+	return rv, p.Err()
+}
+
+// nextLink returns the absolute URL of the rel="next" link, or an empty string on the last page.
+func nextLink(pageURL string, header http.Header) (string, error) {
+	for _, link := range linkheader.Parse(header.Get("Link")) {
+		if link.Rel != "next" {
+			continue
+		}
+
+		base, err := url.Parse(pageURL)
+		if err != nil {
+			return "", err
+		}
+
+		next, err := base.Parse(link.URL)
+		if err != nil {
+			return "", fmt.Errorf("invalid next page link %q: %w", link.URL, err)
+		}
+
+		return next.String(), nil
+	}
+
+	return "", nil
+}
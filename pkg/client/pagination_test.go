@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *FreshdeskClient {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(context.Background(), WithBaseURL(server.URL), WithBearerToken("token"))
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestPaginateFollowsNextLink(t *testing.T) {
+	var requested []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v2/roles?cursor=abc&per_page=2>; rel="next"`, r.Host))
+			_, _ = w.Write([]byte(`[{"id":1},{"id":2}]`))
+		case "abc":
+			_, _ = w.Write([]byte(`[{"id":3}]`))
+		}
+	})
+
+	roles, err := Paginate[Role](c, allRoles, "", WithPageLimit(2)).All(context.Background())
+	require.NoError(t, err)
+
+	var ids []int64
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, []string{"/api/v2/roles?per_page=2", "/api/v2/roles?cursor=abc&per_page=2"}, requested)
+}
+
+func TestPaginateResumesFromCursor(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":` + r.URL.Query().Get("page") + `}]`))
+	})
+
+	roles, cursor, _, err := c.ListRoles(context.Background(), PageOptions{Cursor: c.freshdeskURL + "/api/v2/roles?page=7"})
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	assert.Equal(t, int64(7), roles[0].ID)
+	assert.Empty(t, cursor)
+}
+
+func TestPaginateRejectsForeignHost(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request to %s", r.URL)
+	})
+
+	_, _, _, err := c.ListRoles(context.Background(), PageOptions{Cursor: "https://attacker.example.com/api/v2/roles?page=2"})
+	assert.Error(t, err)
+}
+
+func TestPaginateStopsOnCanceledContext(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request to %s", r.URL)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	paginator := Paginate[Role](c, allRoles, "")
+	assert.False(t, paginator.Next(ctx))
+	assert.ErrorIs(t, paginator.Err(), context.Canceled)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// redactLogger wraps a logger so the given secrets and any email address never reach its output, whether
+// they appear in the message or in a string or error field. Empty secrets are ignored.
+func redactLogger(logger *zap.Logger, secrets ...string) *zap.Logger {
+	var oldnew []string
+	for _, secret := range secrets {
+		if secret != "" {
+			oldnew = append(oldnew, secret, redacted)
+		}
+	}
+
+	replacer := strings.NewReplacer(oldnew...)
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactingCore{Core: core, replacer: replacer}
+	}))
+}
+
+type redactingCore struct {
+	zapcore.Core
+	replacer *strings.Replacer
+}
+
+func (c *redactingCore) redact(text string) string {
+	return redactText(c.replacer.Replace(text))
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redactFields(fields)), replacer: c.replacer}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redact(entry.Message)
+	return c.Core.Write(entry, c.redactFields(fields))
+}
+
+func (c *redactingCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	rv := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		switch f.Type {
+		case zapcore.StringType:
+			f.String = c.redact(f.String)
+		case zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok && err != nil {
+				f = zap.String(f.Key, c.redact(err.Error()))
+			}
+		case zapcore.StringerType:
+			if stringer, ok := f.Interface.(fmt.Stringer); ok && stringer != nil {
+				f = zap.String(f.Key, c.redact(stringer.String()))
+			}
+		}
+		rv = append(rv, f)
+	}
+
+	return rv
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The search API returns 30 results per page and at most 10 pages, so a single query can't
+// return more than 300 results. The page size can't be changed.
+// https://developers.freshdesk.com/api/#filter_contacts
+const (
+	SearchItemsPerPage = 30
+	SearchMaxPages     = 10
+	searchMaxResults   = SearchItemsPerPage * SearchMaxPages
+)
+
+// searchEpoch is the earliest creation date searches are split from. Freshdesk launched in 2010,
+// so no record can be older.
+var searchEpoch = time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+const searchDateLayout = "2006-01-02"
+
+// SearchQuery is a query of the Freshdesk filter language, e.g. email:'jane@acme.com' AND active:true.
+// The zero value matches nothing and is refused by the search methods.
+type SearchQuery struct {
+	expr     string
+	compound bool
+}
+
+// SearchEq matches records whose field equals value. Custom fields are referred to by their name.
+// Strings are quoted, times are compared by date and nil matches an empty field.
+func SearchEq(field string, value interface{}) SearchQuery {
+	return SearchQuery{expr: field + ":" + searchValue(value)}
+}
+
+// SearchGTE matches records whose field is greater than or equal to value, e.g. created_at:>'2024-01-01'.
+func SearchGTE(field string, value interface{}) SearchQuery {
+	return SearchQuery{expr: field + ":>" + searchValue(value)}
+}
+
+// SearchLTE matches records whose field is less than or equal to value, e.g. created_at:<'2024-01-31'.
+func SearchLTE(field string, value interface{}) SearchQuery {
+	return SearchQuery{expr: field + ":<" + searchValue(value)}
+}
+
+// SearchAnd matches records matched by every query. Zero queries are skipped.
+func SearchAnd(queries ...SearchQuery) SearchQuery {
+	return joinSearch("AND", queries)
+}
+
+// SearchOr matches records matched by any of the queries. Zero queries are skipped.
+func SearchOr(queries ...SearchQuery) SearchQuery {
+	return joinSearch("OR", queries)
+}
+
+// IsZero reports whether the query is empty.
+func (q SearchQuery) IsZero() bool {
+	return q.expr == ""
+}
+
+// String returns the query as the search API expects it in the query parameter, wrapped in double quotes.
+func (q SearchQuery) String() string {
+	return `"` + q.expr + `"`
+}
+
+func joinSearch(operator string, queries []SearchQuery) SearchQuery {
+	var parts []string
+	for _, q := range queries {
+		if q.IsZero() {
+			continue
+		}
+
+		if q.compound {
+			parts = append(parts, "("+q.expr+")")
+		} else {
+			parts = append(parts, q.expr)
+		}
+	}
+
+	return SearchQuery{
+		expr:     strings.Join(parts, " "+operator+" "),
+		compound: len(parts) > 1,
+	}
+}
+
+// searchQuoteEscaper escapes the quotes of string values, single ones delimiting the value and double
+// ones the whole query, along with the backslash escaping them.
+var searchQuoteEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `"`, `\"`)
+
+func searchValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + searchQuoteEscaper.Replace(v) + "'"
+	case time.Time:
+		return "'" + v.UTC().Format(searchDateLayout) + "'"
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type searchPage[T any] struct {
+	Results []T `json:"results"`
+	Total   int `json:"total"`
+}
+
+// SearchContacts returns every contact matching the query.
+func (f *FreshdeskClient) SearchContacts(ctx context.Context, query SearchQuery) ([]Contact, error) {
+	return searchAll[Contact](ctx, f, searchContacts, query)
+}
+
+// GetContactByEmail looks a contact up by email. It returns nil when no contact has that email.
+func (f *FreshdeskClient) GetContactByEmail(ctx context.Context, email string) (*Contact, error) {
+	contacts, err := f.SearchContacts(ctx, SearchEq("email", email))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	return &contacts[0], nil
+}
+
+// searchAll reads every result of a query. When the query matches more records than the search API
+// returns, it is split into creation date ranges until each range fits.
+func searchAll[T any](ctx context.Context, f *FreshdeskClient, path string, query SearchQuery) ([]T, error) {
+	if query.IsZero() {
+		return nil, fmt.Errorf("baton-freshdesk: empty search query")
+	}
+
+	page, err := searchPageOf[T](ctx, f, path, query, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Total <= searchMaxResults {
+		return searchRemainingPages(ctx, f, path, query, page)
+	}
+
+	return searchCreatedBetween[T](ctx, f, path, query, searchEpoch, time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1))
+}
+
+// searchCreatedBetween reads the results of a query created between two dates, both inclusive,
+// halving the range while it matches too many records.
+func searchCreatedBetween[T any](ctx context.Context, f *FreshdeskClient, path string, query SearchQuery, from, to time.Time) ([]T, error) {
+	ranged := SearchAnd(query, SearchGTE("created_at", from), SearchLTE("created_at", to))
+
+	page, err := searchPageOf[T](ctx, f, path, ranged, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Total <= searchMaxResults {
+		return searchRemainingPages(ctx, f, path, ranged, page)
+	}
+
+	days := int(to.Sub(from).Hours() / 24)
+	if days < 1 {
+		return nil, fmt.Errorf("baton-freshdesk: search %s matches %d records created on %s, more than the %d the search API returns",
+			query, page.Total, from.Format(searchDateLayout), searchMaxResults)
+	}
+
+	mid := from.AddDate(0, 0, days/2)
+
+	rv, err := searchCreatedBetween[T](ctx, f, path, query, from, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	rest, err := searchCreatedBetween[T](ctx, f, path, query, mid.AddDate(0, 0, 1), to)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(rv, rest...), nil
+}
+
+// searchRemainingPages reads the pages that follow the first one of a query that fits in the search limits.
+func searchRemainingPages[T any](ctx context.Context, f *FreshdeskClient, path string, query SearchQuery, first *searchPage[T]) ([]T, error) {
+	rv := first.Results
+	last := first
+
+	for pageNumber := 2; pageNumber <= SearchMaxPages && len(last.Results) == SearchItemsPerPage; pageNumber++ {
+		page, err := searchPageOf[T](ctx, f, path, query, pageNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		rv = append(rv, page.Results...)
+		last = page
+	}
+
+	return rv, nil
+}
+
+func searchPageOf[T any](ctx context.Context, f *FreshdeskClient, path string, query SearchQuery, pageNumber int) (*searchPage[T], error) {
+	queryUrl, err := url.JoinPath(f.freshdeskURL, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *searchPage[T]
+	_, _, err = f.doRequest(ctx, http.MethodGet, queryUrl, &res, nil,
+		WithQueryParam("query", query.String()),
+		WithPage(pageNumber),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return &searchPage[T]{}, nil
+	}
+
+	return res, nil
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchQueryString(t *testing.T) {
+	query := SearchAnd(
+		SearchEq("email", "o'neil@acme.com"),
+		SearchEq("nickname", `"J\"`),
+		SearchOr(SearchEq("company_id", 12), SearchEq("region", nil)),
+		SearchEq("active", true),
+		SearchQuery{},
+	)
+
+	assert.Equal(t, `"email:'o\'neil@acme.com' AND nickname:'\"J\\\"' AND (company_id:12 OR region:null) AND active:true"`, query.String())
+}
+
+func TestSearchContactsReadsEveryPage(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, searchContacts, r.URL.Path)
+		assert.Equal(t, `"company_id:12"`, r.URL.Query().Get("query"))
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			_, _ = w.Write([]byte(`{"total":31,"results":[` + strings.Repeat(`{"id":1},`, 29) + `{"id":1}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"total":31,"results":[{"id":2}]}`))
+	})
+
+	contacts, err := c.SearchContacts(context.Background(), SearchEq("company_id", 12))
+	require.NoError(t, err)
+	assert.Len(t, contacts, 31)
+	assert.Equal(t, []string{"1", "2"}, pages)
+}
+
+func TestSearchSplitsQueriesOverTheLimit(t *testing.T) {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(searchDateLayout)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		// Both the query and its whole creation date range match too many contacts, each half fits.
+		query := r.URL.Query().Get("query")
+		wholeRange := strings.Contains(query, "created_at:>'2010-01-01'") && strings.Contains(query, "created_at:<'"+tomorrow+"'")
+		if !strings.Contains(query, "created_at") || wholeRange {
+			_, _ = w.Write([]byte(`{"total":400,"results":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"total":1,"results":[{"id":1}]}`))
+	})
+
+	contacts, err := c.SearchContacts(context.Background(), SearchEq("active", true))
+	require.NoError(t, err)
+	assert.Len(t, contacts, 2)
+}
+
+func TestSearchRefusesEmptyQuery(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request expected")
+	})
+
+	_, err := c.SearchContacts(context.Background(), SearchQuery{})
+	require.Error(t, err)
+}
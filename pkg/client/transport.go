@@ -0,0 +1,193 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxLoggedBody caps the bytes of a request or response body written to the logs.
+const maxLoggedBody = 4096
+
+// rateLimitHeaders are the Freshdesk rate limit headers logged with every response.
+var rateLimitHeaders = []string{"X-Ratelimit-Total", "X-Ratelimit-Remaining", "X-Ratelimit-Used-Currentrequest", "Retry-After"}
+
+// piiFields are the query parameters and JSON fields holding personal data, masked in the logs.
+var piiFields = map[string]bool{
+	"email":             true,
+	"other_emails":      true,
+	"phone":             true,
+	"mobile":            true,
+	"work_phone_number": true,
+	"address":           true,
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// searchPIIPattern matches the personal data fields of a search query with their value, quoted or not,
+// e.g. phone:'+1 555 0100'.
+var searchPIIPattern = regexp.MustCompile(`\b(` + strings.Join(sortedKeys(piiFields), "|") + `):(?:'(?:[^'\\]|\\.)*'|[^\s)"]+)`)
+
+// loggingTransport logs every Freshdesk request at debug level: method, path, status, latency and the
+// rate limit headers. The Authorization header and personal data are masked; bodies are only logged
+// when enabled, as they may hold more personal data than the fields masked.
+type loggingTransport struct {
+	next      http.RoundTripper
+	logger    *zap.Logger
+	logBodies bool
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.logger.Core().Enabled(zap.DebugLevel) {
+		return t.next.RoundTrip(req)
+	}
+
+	fields := []zap.Field{
+		zap.String("http.method", req.Method),
+		zap.String("http.url_details.host", req.URL.Host),
+		zap.String("http.url_details.path", req.URL.Path),
+		zap.String("http.url_details.query", redactQuery(req.URL.Query())),
+		zap.Any("http.request.headers", redactHeaders(req.Header)),
+	}
+
+	if t.logBodies && req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 && string(body) != "null" {
+			fields = append(fields, zap.String("http.request.body", redactBody(body)))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields = append(fields, zap.Duration("http.latency", time.Since(start)))
+
+	if err != nil {
+		t.logger.Debug("Freshdesk request failed", append(fields, zap.String("error", redactText(err.Error())))...)
+		return resp, err
+	}
+
+	fields = append(fields, zap.Int("http.status_code", resp.StatusCode))
+	for _, header := range rateLimitHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			fields = append(fields, zap.String("http.response.headers."+strings.ToLower(header), value))
+		}
+	}
+
+	if t.logBodies && resp.Body != nil {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		fields = append(fields, zap.String("http.response.body", redactBody(body)))
+	}
+
+	t.logger.Debug("Freshdesk request complete", fields...)
+
+	return resp, nil
+}
+
+// redactHeaders returns the headers of a request with the credentials masked.
+func redactHeaders(header http.Header) map[string]string {
+	rv := make(map[string]string, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization":
+			scheme, _, _ := strings.Cut(value, " ")
+			value = scheme + " " + redacted
+		case "Cookie":
+			value = redacted
+		}
+		rv[name] = value
+	}
+
+	return rv
+}
+
+// redactQuery returns the query of a request with the personal data masked.
+func redactQuery(query url.Values) string {
+	for key, values := range query {
+		for i := range values {
+			if piiFields[key] {
+				values[i] = redacted
+				continue
+			}
+			values[i] = redactText(values[i])
+		}
+	}
+
+	return query.Encode()
+}
+
+// redactBody returns a body as logged: JSON bodies with their personal data fields masked, other bodies
+// with the email addresses masked, truncated to maxLoggedBody bytes.
+func redactBody(body []byte) string {
+	var value interface{}
+	rv := redactText(string(body))
+	if err := json.Unmarshal(body, &value); err == nil {
+		if redactedBody, err := json.Marshal(redactJSON(value)); err == nil {
+			rv = string(redactedBody)
+		}
+	}
+
+	if len(rv) > maxLoggedBody {
+		return rv[:maxLoggedBody] + "...(truncated)"
+	}
+
+	return rv
+}
+
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if piiFields[key] && field != nil {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactJSON(field)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+		return v
+	case string:
+		return redactText(v)
+	default:
+		return v
+	}
+}
+
+// redactText masks the email addresses of free text, such as search queries and error messages, and the
+// values of the personal data fields of search queries.
+func redactText(text string) string {
+	text = searchPIIPattern.ReplaceAllString(text, "$1:"+redacted)
+	return emailPattern.ReplaceAllString(text, redacted)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
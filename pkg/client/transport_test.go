@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLoggingTransportMasksCredentialsAndPersonalData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Remaining", "42")
+		_, _ = w.Write([]byte(`[{"id":7,"contact":{"name":"Jane","email":"jane@acme.com","mobile":"+1 555 0100"}}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	var out bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.AddSync(&out), zap.DebugLevel)
+	ctx := ctxzap.ToContext(context.Background(), zap.New(core))
+
+	c, err := New(ctx, WithBaseURL(server.URL), WithBearerToken("secret-key"), WithBodyLogging(true))
+	require.NoError(t, err)
+
+	agents, _, _, err := c.ListAgents(ctx, PageOptions{}, WithAgentEmail("jane@acme.com"))
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "jane@acme.com", agents[0].Contact.Email)
+
+	logs := out.String()
+	assert.Contains(t, logs, "Freshdesk request complete")
+	assert.Contains(t, logs, `"http.status_code":200`)
+	assert.Contains(t, logs, `"http.response.headers.x-ratelimit-remaining":"42"`)
+	assert.Contains(t, logs, `"Authorization":"Basic `+redacted)
+	assert.Contains(t, logs, `\"name\":\"Jane\"`)
+	assert.NotContains(t, logs, "secret-key")
+	assert.NotContains(t, logs, basicAuth("secret-key", "X"))
+	assert.NotContains(t, logs, "jane@acme.com")
+	assert.NotContains(t, logs, "555")
+}
+
+func TestLoggingTransportSkipsBodiesByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"Acme"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var out bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.AddSync(&out), zap.DebugLevel)
+	ctx := ctxzap.ToContext(context.Background(), zap.New(core))
+
+	c, err := New(ctx, WithBaseURL(server.URL), WithBearerToken("secret-key"))
+	require.NoError(t, err)
+
+	_, _, err = c.GetAccount(ctx)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "Freshdesk request complete")
+	assert.NotContains(t, out.String(), "http.response.body")
+}
+
+func TestRedactText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: `"email:'jane@acme.com'"`, want: `"email:` + redacted + `"`},
+		{text: `"phone:'+1 555 0100' OR mobile:'555 \'0101\''"`, want: `"phone:` + redacted + ` OR mobile:` + redacted + `"`},
+		{text: `"(work_phone_number:5550100 AND company_id:42)"`, want: `"(work_phone_number:` + redacted + ` AND company_id:42)"`},
+		{text: `"name:'Jane' AND active:true"`, want: `"name:'Jane' AND active:true"`},
+		{text: "no agent with email jane@acme.com", want: "no agent with email " + redacted},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, redactText(tt.text))
+	}
+}
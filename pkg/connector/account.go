@@ -0,0 +1,375 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	accountOwnerEntitlement = "owner"
+	accountAdminEntitlement = "admin"
+
+	// Support agents hold one of the license entitlements. They are provisioned by switching the
+	// occasional flag of the agent, within the seats configured for the account.
+	accountFullTimeLicenseEntitlement   = "full_time_license"
+	accountOccasionalLicenseEntitlement = "occasional_license"
+
+	// accountAdminRoleName is the name of the built-in Freshdesk role that holds full control of the account.
+	accountAdminRoleName = "Account Administrator"
+)
+
+type accountBuilder struct {
+	resourceType   *v2.ResourceType
+	accounts       *accountSet
+	grantsPageSize int
+
+	adminRoleMutex sync.Mutex
+	adminRoleIDs   map[string]int64
+}
+
+func (a *accountBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return a.resourceType
+}
+
+// List returns one resource per configured Freshdesk account, described by the account endpoint
+// along with the seats its agents use. Users, roles, groups, companies, contact segments, products,
+// mailboxes, canned response folders, ticket templates, automations, business hours, SLA policies and
+// knowledge-base categories are listed under them.
+func (a *accountBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	for _, account := range a.accounts.accounts {
+		account.startSync()
+
+		accountDetail, _, err := account.client.GetAccount(ctx)
+		if err != nil {
+			// The account endpoint is reserved to account administrators. Other keys still sync
+			// everything else, under an account described by its domain only.
+			if status.Code(err) != codes.PermissionDenied {
+				return nil, "", nil, err
+			}
+			ctxzap.Extract(ctx).Warn("baton-freshdesk: the API key cannot read the account details, describing the account by its domain",
+				zap.String("domain", account.domain),
+				zap.Error(err))
+			accountDetail = &client.Account{}
+		}
+
+		usage, err := account.seatUsage(ctx)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		accountResource, err := parseIntoAccountResource(account, accountDetail, usage)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, accountResource)
+	}
+
+	return rv, "", nil, nil
+}
+
+// Entitlements returns the owner of the account, the contact person Freshdesk bills, and its administrators,
+// the agents holding the Account Administrator role. Both are computed, so they can't be provisioned here.
+// It also returns the full-time and occasional licenses support agents hold.
+func (a *accountBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		accountOwnerEntitlement,
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Account Owner", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Contact person and owner of the %s Freshdesk account", resource.DisplayName)),
+	))
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		accountAdminEntitlement,
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Account Admin", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Agents holding the %s role in the %s Freshdesk account", accountAdminRoleName, resource.DisplayName)),
+	))
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		accountFullTimeLicenseEntitlement,
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Full-Time License", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Support agents using a full-time seat of the %s Freshdesk account", resource.DisplayName)),
+	))
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		accountOccasionalLicenseEntitlement,
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Occasional License", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Support agents using day passes of the %s Freshdesk account", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants pages over the agents of the account, like the role and group builders do.
+func (a *accountBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, err := a.accounts.fromAccountResourceID(resource.Id)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	adminRoleID, err := a.adminRoleID(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	appTrait, err := rs.GetAppTrait(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	ownerEmail, _ := rs.GetProfileStringValue(appTrait.Profile, "owner_email")
+
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	agents, nextPageToken, annotation, err := account.client.ListAgents(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: a.grantsPageSize,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	agentsDetails, err := account.agentDetails.GetAgentsDetails(ctx, agents)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, agentDetail := range agentsDetails {
+		if !account.filters.agentIncluded(&agentDetail) {
+			continue
+		}
+
+		userID := &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     a.accounts.resourceID(account, agentDetail.ID),
+		}
+
+		if ownerEmail != "" && strings.EqualFold(agentDetail.Contact.Email, ownerEmail) {
+			rv = append(rv, grant.NewGrant(resource, accountOwnerEntitlement, userID))
+		}
+
+		if adminRoleID != 0 && slices.Contains(agentDetail.RoleIDs, adminRoleID) {
+			rv = append(rv, grant.NewGrant(resource, accountAdminEntitlement, userID))
+		}
+
+		if license, ok := licenseEntitlement(&agentDetail); ok {
+			rv = append(rv, grant.NewGrant(resource, license, userID))
+		}
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+// Grant assigns a full-time or occasional license to a support agent. It refuses when the account has no
+// seat of that kind left.
+func (a *accountBuilder) Grant(ctx context.Context, principal *v2.Resource, ent *v2.Entitlement) (annotations.Annotations, error) {
+	if principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-freshdesk: only users can be granted a license")
+	}
+
+	var occasional bool
+	switch entitlementSlug(ent.Id) {
+	case accountFullTimeLicenseEntitlement:
+		occasional = false
+	case accountOccasionalLicenseEntitlement:
+		occasional = true
+	default:
+		return nil, fmt.Errorf("baton-freshdesk: entitlement %s is computed by Freshdesk and can't be granted", ent.Id)
+	}
+
+	return a.setLicense(ctx, ent.Resource.Id, principal.Id.Resource, occasional)
+}
+
+// Revoke downgrades a full-time agent to day passes. Every support agent holds one license, so the
+// occasional license can only be replaced by granting the full-time one.
+func (a *accountBuilder) Revoke(ctx context.Context, g *v2.Grant) (annotations.Annotations, error) {
+	switch entitlementSlug(g.Entitlement.Id) {
+	case accountFullTimeLicenseEntitlement:
+		return a.setLicense(ctx, g.Entitlement.Resource.Id, g.Principal.Id.Resource, true)
+	case accountOccasionalLicenseEntitlement:
+		return nil, fmt.Errorf("baton-freshdesk: support agents always hold a license, grant the full-time license instead of revoking the occasional one")
+	default:
+		return nil, fmt.Errorf("baton-freshdesk: entitlement %s is computed by Freshdesk and can't be revoked", g.Entitlement.Id)
+	}
+}
+
+func (a *accountBuilder) setLicense(ctx context.Context, accountResourceID *v2.ResourceId, userResourceID string, occasional bool) (annotations.Annotations, error) {
+	account, err := a.accounts.fromAccountResourceID(accountResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	userAccount, agentID, err := a.accounts.parseResourceID(userResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userAccount != account {
+		return nil, fmt.Errorf("baton-freshdesk: user %s doesn't belong to account %s", userResourceID, account.domain)
+	}
+
+	agent, _, err := account.client.GetAgentDetail(ctx, strconv.FormatInt(agentID, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := licenseEntitlement(agent); !ok {
+		return nil, fmt.Errorf("baton-freshdesk: agent %s is a %s and doesn't use a support agent license", userResourceID, agent.Type)
+	}
+
+	if agent.Occasional == occasional {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	wanted := *agent
+	wanted.Occasional = occasional
+	err = account.checkSeatAvailable(ctx, seatCategory(&wanted))
+	if err != nil {
+		return nil, err
+	}
+
+	return account.client.UpdateAgentOccasional(ctx, agentID, occasional)
+}
+
+// licenseEntitlement returns the license entitlement a support agent holds. Field technicians and
+// collaborators use seats of their own and hold none.
+func licenseEntitlement(agent *client.Agent) (string, bool) {
+	switch seatCategory(agent) {
+	case seatFullTime:
+		return accountFullTimeLicenseEntitlement, true
+	case seatOccasional:
+		return accountOccasionalLicenseEntitlement, true
+	default:
+		return "", false
+	}
+}
+
+// adminRoleID returns the ID of the Account Administrator role of the account, or 0 if there is none.
+func (a *accountBuilder) adminRoleID(ctx context.Context, account *account) (int64, error) {
+	a.adminRoleMutex.Lock()
+	defer a.adminRoleMutex.Unlock()
+
+	if roleID, ok := a.adminRoleIDs[account.domain]; ok {
+		return roleID, nil
+	}
+
+	roles, err := account.client.ListAllRoles(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var roleID int64
+	for _, role := range roles {
+		if role.Default && role.Name == accountAdminRoleName {
+			roleID = role.ID
+			break
+		}
+	}
+	a.adminRoleIDs[account.domain] = roleID
+
+	return roleID, nil
+}
+
+func newAccountBuilder(accounts *accountSet, grantsPageSize int) *accountBuilder {
+	return &accountBuilder{
+		resourceType:   accountResourceType,
+		accounts:       accounts,
+		grantsPageSize: grantsPageSize,
+		adminRoleIDs:   make(map[string]int64),
+	}
+}
+
+// parseIntoAccountResource - This function parses a Freshdesk account into an Account Resource.
+// Seat usage and the configured seat limits are added as seats_* and agents_* profile fields.
+func parseIntoAccountResource(account *account, accountDetail *client.Account, usage *seatUsage) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"domain":               account.domain,
+		"account_id":           accountDetail.AccountID,
+		"account_name":         accountDetail.AccountName,
+		"plan":                 accountDetail.TierType,
+		"data_center":          accountDetail.DataCenter,
+		"timezone":             accountDetail.Timezone,
+		"full_time_agents":     accountDetail.TotalAgents.FullTime,
+		"occasional_agents":    accountDetail.TotalAgents.Occasional,
+		"field_service_agents": accountDetail.TotalAgents.FieldService,
+		"collaborators":        accountDetail.TotalAgents.Collaborators,
+		"owner_name":           strings.TrimSpace(accountDetail.ContactPerson.FirstName + " " + accountDetail.ContactPerson.LastName),
+		"owner_email":          accountDetail.ContactPerson.Email,
+	}
+
+	for key, value := range usage.profile(account.seatLimits) {
+		profile[key] = value
+	}
+
+	appTraits := []rs.AppTraitOption{
+		rs.WithAppProfile(profile),
+	}
+
+	displayName := accountDetail.AccountName
+	if displayName == "" {
+		displayName = account.domain
+	}
+
+	return rs.NewAppResource(
+		displayName,
+		accountResourceType,
+		account.domain,
+		appTraits,
+		rs.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: userResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: contactResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: roleResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: groupResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: companyResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: contactSegmentResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: productResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: emailConfigResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: cannedResponseFolderResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: ticketTemplateResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: scenarioAutomationResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: automationRuleResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: businessHourResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: slaPolicyResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: solutionCategoryResourceType.Id},
+		),
+	)
+}
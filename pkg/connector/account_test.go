@@ -0,0 +1,78 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServedAccountSet(t *testing.T, accountStatus int) *accountSet {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/account":
+			w.WriteHeader(accountStatus)
+			if accountStatus == http.StatusOK {
+				_, _ = w.Write([]byte(`{"account_id":42,"account_name":"Acme Support","tier_type":"Pro",` +
+					`"contact_person":{"first_name":"Jane","last_name":"Doe","email":"jane@acme.com"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":"access_denied","message":"You are not authorized to perform this action."}`))
+		case "/api/v2/agents":
+			_, _ = w.Write([]byte(`[{"id":1,"type":"support_agent"},{"id":2,"type":"support_agent","occasional":true}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	return newAccountSet(&account{
+		domain:       "acme",
+		client:       c,
+		agentDetails: newAgentDetailFetcher(c, defaultAgentDetailsConcurrency),
+	})
+}
+
+func TestAccountBuilderList(t *testing.T) {
+	accounts := newTestServedAccountSet(t, http.StatusOK)
+
+	resources, _, _, err := newAccountBuilder(accounts, client.ItemsPerPage).List(ctx, nil, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	assert.Equal(t, "Acme Support", resources[0].DisplayName)
+	appTrait, err := rs.GetAppTrait(resources[0])
+	require.NoError(t, err)
+	ownerEmail, _ := rs.GetProfileStringValue(appTrait.Profile, "owner_email")
+	assert.Equal(t, "jane@acme.com", ownerEmail)
+}
+
+func TestAccountBuilderListWithoutAccountAccess(t *testing.T) {
+	accounts := newTestServedAccountSet(t, http.StatusForbidden)
+
+	resources, _, _, err := newAccountBuilder(accounts, client.ItemsPerPage).List(ctx, nil, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	assert.Equal(t, "acme", resources[0].DisplayName)
+	assert.Equal(t, "acme", resources[0].Id.Resource)
+	appTrait, err := rs.GetAppTrait(resources[0])
+	require.NoError(t, err)
+	ownerEmail, _ := rs.GetProfileStringValue(appTrait.Profile, "owner_email")
+	assert.Empty(t, ownerEmail)
+	seats, ok := rs.GetProfileInt64Value(appTrait.Profile, "seats_used_occasional")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), seats)
+}
@@ -0,0 +1,125 @@
+package connector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+// accountIDSeparator separates the account domain from the Freshdesk ID in the resource IDs
+// of the accounts other than the primary one.
+const accountIDSeparator = "/"
+
+// AccountConfig holds the credentials of one Freshdesk account (helpdesk). When APIKeySource is set,
+// the API key is read from it instead of APIKey.
+type AccountConfig struct {
+	Domain       string
+	APIKey       string
+	APIKeySource client.APIKeySource
+}
+
+// account is a Freshdesk account the connector syncs, along with the client and caches scoped to it.
+type account struct {
+	domain       string
+	client       *client.FreshdeskClient
+	agentDetails *agentDetailFetcher
+	seatLimits   map[string]int64
+	filters      *Filters
+
+	// syncMutex guards the lists the builders share during a sync, see syncCached.
+	syncMutex sync.Mutex
+	syncCache map[string]*syncCacheEntry
+
+	// seatsMutex guards the seat usage last counted, reused by seat checks until seatUsageTTL elapses.
+	seatsMutex     sync.Mutex
+	seats          *seatUsage
+	seatsCountedAt time.Time
+}
+
+// accountSet holds every configured Freshdesk account.
+// Users, roles and groups are nested under the account resource they come from. Freshdesk IDs are
+// only unique within an account, so the resource IDs of every account but the primary one, the first
+// configured, are prefixed with the account domain (acme-us/42). The primary account keeps the plain
+// Freshdesk IDs, so adding accounts never renames the resources synced before; making another account
+// the primary one does.
+type accountSet struct {
+	accounts []*account
+	byDomain map[string]*account
+}
+
+func newAccountSet(accounts ...*account) *accountSet {
+	set := &accountSet{
+		byDomain: make(map[string]*account),
+	}
+
+	for _, a := range accounts {
+		set.accounts = append(set.accounts, a)
+		set.byDomain[a.domain] = a
+	}
+
+	return set
+}
+
+// primary returns the account whose resource IDs carry no domain.
+func (s *accountSet) primary() *account {
+	return s.accounts[0]
+}
+
+// resourceID returns the resource ID of the Freshdesk object id living in account a.
+func (s *accountSet) resourceID(a *account, id int64) string {
+	objectID := strconv.FormatInt(id, 10)
+	if a == s.primary() {
+		return objectID
+	}
+
+	return a.domain + accountIDSeparator + objectID
+}
+
+// parseResourceID splits a resource ID built by resourceID into its account and Freshdesk ID.
+func (s *accountSet) parseResourceID(resourceID string) (*account, int64, error) {
+	a := s.primary()
+	objectID := resourceID
+
+	if domain, id, found := strings.Cut(resourceID, accountIDSeparator); found {
+		var ok bool
+		a, ok = s.byDomain[domain]
+		if !ok || a == s.primary() {
+			return nil, 0, fmt.Errorf("baton-freshdesk: unknown account %s", domain)
+		}
+		objectID = id
+	}
+
+	id, err := strconv.ParseInt(objectID, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("baton-freshdesk: invalid resource ID %s: %w", resourceID, err)
+	}
+
+	return a, id, nil
+}
+
+// fromAccountResourceID returns the account behind an account resource, such as the parent of a child resource.
+func (s *accountSet) fromAccountResourceID(resourceID *v2.ResourceId) (*account, error) {
+	if resourceID == nil || resourceID.ResourceType != accountResourceType.Id {
+		return nil, fmt.Errorf("baton-freshdesk: expected an account resource, got %v", resourceID)
+	}
+
+	a, ok := s.byDomain[resourceID.Resource]
+	if !ok {
+		return nil, fmt.Errorf("baton-freshdesk: unknown account %s", resourceID.Resource)
+	}
+
+	return a, nil
+}
+
+// accountResourceID returns the ID of the account resource that is the parent of every resource of a.
+func accountResourceID(a *account) *v2.ResourceId {
+	return &v2.ResourceId{
+		ResourceType: accountResourceType.Id,
+		Resource:     a.domain,
+	}
+}
@@ -0,0 +1,42 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountSetResourceIDs(t *testing.T) {
+	eu := &account{domain: "acme-eu"}
+	us := &account{domain: "acme-us"}
+
+	single := newAccountSet(eu)
+	assert.Equal(t, "42", single.resourceID(eu, 42))
+
+	a, id, err := single.parseResourceID("42")
+	require.NoError(t, err)
+	assert.Same(t, eu, a)
+	assert.Equal(t, int64(42), id)
+
+	// Adding an account keeps the IDs of the primary one.
+	multi := newAccountSet(eu, us)
+	assert.Equal(t, "42", multi.resourceID(eu, 42))
+	assert.Equal(t, "acme-us/42", multi.resourceID(us, 42))
+
+	a, id, err = multi.parseResourceID("acme-us/42")
+	require.NoError(t, err)
+	assert.Same(t, us, a)
+	assert.Equal(t, int64(42), id)
+
+	a, id, err = multi.parseResourceID("42")
+	require.NoError(t, err)
+	assert.Same(t, eu, a)
+	assert.Equal(t, int64(42), id)
+
+	_, _, err = multi.parseResourceID("acme-eu/42")
+	assert.Error(t, err)
+
+	_, _, err = multi.parseResourceID("acme-apac/42")
+	assert.Error(t, err)
+}
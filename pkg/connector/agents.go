@@ -0,0 +1,193 @@
+package connector
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+)
+
+const (
+	defaultAgentDetailsConcurrency = 5
+
+	// rateLimitPollInterval is how long an idle worker waits before checking the request budget again.
+	rateLimitPollInterval = time.Second
+)
+
+// agentDetailFetcher fetches and caches the detailed representation of agents, which is the only
+// one carrying their role and group IDs. It is shared by the role and group builders.
+type agentDetailFetcher struct {
+	client      *client.FreshdeskClient
+	concurrency int
+
+	mtx     sync.RWMutex
+	details map[int64]client.Agent
+}
+
+func newAgentDetailFetcher(c *client.FreshdeskClient, concurrency int) *agentDetailFetcher {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &agentDetailFetcher{
+		client:      c,
+		concurrency: concurrency,
+		details:     make(map[int64]client.Agent),
+	}
+}
+
+// GetAgentsDetails returns the details of the given agents, in the same order.
+// Agents that are not cached yet are fetched by a bounded pool of workers. An agent Freshdesk returns
+// no details for is returned as listed.
+func (a *agentDetailFetcher) GetAgentsDetails(ctx context.Context, agents []client.Agent) ([]client.Agent, error) {
+	var missing []int64
+	a.mtx.RLock()
+	for _, agent := range agents {
+		if _, ok := a.details[agent.ID]; !ok {
+			missing = append(missing, agent.ID)
+		}
+	}
+	a.mtx.RUnlock()
+
+	if len(missing) > 0 {
+		err := a.fetch(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	rv := make([]client.Agent, 0, len(agents))
+	for _, agent := range agents {
+		detail, ok := a.details[agent.ID]
+		if !ok {
+			detail = agent
+		}
+		rv = append(rv, detail)
+	}
+
+	return rv, nil
+}
+
+// reset drops the cached details, so that they are fetched again.
+func (a *agentDetailFetcher) reset() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.details = make(map[int64]client.Agent)
+}
+
+func (a *agentDetailFetcher) fetch(ctx context.Context, agentIDs []int64) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ids := make(chan int64)
+	errs := make(chan error, a.concurrency)
+	budget := newRequestBudget(a.client)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(a.concurrency, len(agentIDs)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				err := budget.acquire(ctx)
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+
+				detail, _, err := a.client.GetAgentDetail(ctx, strconv.FormatInt(id, 10))
+				budget.release()
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+
+				// An empty answer leaves the agent as listed.
+				if detail == nil {
+					continue
+				}
+
+				a.mtx.Lock()
+				a.details[id] = *detail
+				a.mtx.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, id := range agentIDs {
+		select {
+		case ids <- id:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(ids)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// requestBudget keeps the requests in flight below the remaining Freshdesk request budget.
+// A request proceeds when the last response left more requests than are in flight, or when none is in
+// flight: then no response is coming to refresh the budget, and Freshdesk's rate limit response drives
+// the retry instead.
+type requestBudget struct {
+	client *client.FreshdeskClient
+
+	mtx      sync.Mutex
+	inFlight int64
+	released chan struct{}
+}
+
+func newRequestBudget(c *client.FreshdeskClient) *requestBudget {
+	return &requestBudget{
+		client:   c,
+		released: make(chan struct{}),
+	}
+}
+
+// acquire blocks until a request may be sent. Every successful acquire must be followed by a release.
+func (b *requestBudget) acquire(ctx context.Context) error {
+	for {
+		b.mtx.Lock()
+		remaining, ok := b.client.RateLimitRemaining()
+		if b.inFlight == 0 || !ok || remaining > b.inFlight {
+			b.inFlight++
+			b.mtx.Unlock()
+			return ctx.Err()
+		}
+		released := b.released
+		b.mtx.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		case <-time.After(rateLimitPollInterval):
+		}
+	}
+}
+
+// release ends a request and wakes the requests waiting for the budget.
+func (b *requestBudget) release() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.inFlight--
+	close(b.released)
+	b.released = make(chan struct{})
+}
@@ -0,0 +1,120 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAgentDetailFetcher(t *testing.T, concurrency int, handler http.HandlerFunc) (*agentDetailFetcher, *client.FreshdeskClient) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	return newAgentDetailFetcher(c, concurrency), c
+}
+
+func testAgents(count int) []client.Agent {
+	agents := make([]client.Agent, 0, count)
+	for i := 1; i <= count; i++ {
+		agents = append(agents, client.Agent{ID: int64(i)})
+	}
+
+	return agents
+}
+
+func TestAgentDetailsStopOnCancellation(t *testing.T) {
+	release := make(chan struct{})
+	fetcher, _ := newTestAgentDetailFetcher(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+	t.Cleanup(func() { close(release) })
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	done := make(chan error)
+	go func() {
+		_, err := fetcher.GetAgentsDetails(cancelCtx, testAgents(10))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the pool didn't stop once cancelled")
+	}
+}
+
+func TestAgentDetailsReturnTheFirstError(t *testing.T) {
+	fetcher, _ := newTestAgentDetailFetcher(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/4") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+
+	_, err := fetcher.GetAgentsDetails(ctx, testAgents(10))
+	require.Error(t, err)
+}
+
+func TestAgentDetailsWithLowBudget(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int64
+	fetcher, c := newTestAgentDetailFetcher(t, 5, func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Remaining", "0")
+		_, _ = w.Write([]byte(`{"id":` + id + `,"role_ids":[` + id + `]}`))
+	})
+
+	// Exhaust the budget before the pool starts.
+	_, _, err := c.GetAgentDetail(ctx, "100")
+	require.NoError(t, err)
+	maxInFlight.Store(0)
+
+	details, err := fetcher.GetAgentsDetails(ctx, testAgents(10))
+	require.NoError(t, err)
+	require.Len(t, details, 10)
+	assert.Equal(t, []int64{10}, details[9].RoleIDs)
+	assert.Equal(t, int64(1), maxInFlight.Load())
+}
+
+func TestAgentDetailsKeepListedAgentOnEmptyAnswer(t *testing.T) {
+	fetcher, _ := newTestAgentDetailFetcher(t, 2, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`null`))
+	})
+
+	agents := []client.Agent{{ID: 1, Type: "support_agent"}}
+	details, err := fetcher.GetAgentsDetails(ctx, agents)
+	require.NoError(t, err)
+	assert.Equal(t, agents, details)
+}
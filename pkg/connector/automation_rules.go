@@ -0,0 +1,230 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	automationEditorEntitlement = "editor"
+
+	// adminRoleName is the name of the built-in Freshdesk role that, like the Account Administrator,
+	// can manage automations.
+	adminRoleName = "Administrator"
+)
+
+type automationRuleBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (a *automationRuleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return a.resourceType
+}
+
+// List returns the automation rules of every automation type of the account. Freshdesk returns the rules
+// of each type at once, so they are listed in a single page.
+func (a *automationRuleBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := a.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	rules, err := a.accountRules(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	ruleIDs := make([]int64, 0, len(rules))
+	for ruleID := range rules {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	slices.Sort(ruleIDs)
+
+	for _, ruleID := range ruleIDs {
+		rule := rules[ruleID]
+		ruleResource, err := parseIntoAutomationRuleResource(&rule, a.accounts.resourceID(account, rule.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, ruleResource)
+	}
+
+	return rv, "", nil, nil
+}
+
+func (a *automationRuleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		automationEditorEntitlement,
+		entitlement.WithGrantableTo(roleResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Rule Editor", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Can change or disable the %s automation rule", resource.DisplayName)),
+	))
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		automationCreatorEntitlement,
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Rule Creator", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Created the %s automation rule", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants grants the rule to the built-in administrator roles, expanded to the agents holding them, and to
+// its creator. Custom roles may manage automations as well, but Freshdesk doesn't expose role privileges.
+func (a *automationRuleBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, ruleID, err := a.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	roleIDs, err := a.accountEditorRoles(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, roleID := range roleIDs {
+		roleResource := &v2.Resource{
+			Id: &v2.ResourceId{
+				ResourceType: roleResourceType.Id,
+				Resource:     a.accounts.resourceID(account, roleID),
+			},
+		}
+
+		rv = append(rv, grant.NewGrant(
+			resource,
+			automationEditorEntitlement,
+			roleResource.Id,
+			grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{entitlement.NewEntitlementID(roleResource, roleAssignedEntitlement)},
+			}),
+		))
+	}
+
+	rules, err := a.accountRules(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	rule := rules[ruleID]
+	creatorInScope := false
+	if rule.CreatedBy != 0 {
+		creatorInScope, err = account.agentInScope(ctx, rule.CreatedBy)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	if creatorInScope {
+		rv = append(rv, grant.NewGrant(resource, automationCreatorEntitlement, &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     a.accounts.resourceID(account, rule.CreatedBy),
+		}))
+	}
+
+	return rv, "", nil, nil
+}
+
+// accountRules returns the rules of every automation type of the account by ID, read once per sync.
+func (a *automationRuleBuilder) accountRules(ctx context.Context, account *account) (map[int64]client.AutomationRule, error) {
+	return syncCached(account, "automation_rules", func() (map[int64]client.AutomationRule, error) {
+		rules := make(map[int64]client.AutomationRule)
+		for _, automationType := range client.AutomationTypes {
+			list, err := account.client.ListAllAutomationRules(ctx, automationType)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, rule := range list {
+				rules[rule.ID] = rule
+			}
+		}
+
+		return rules, nil
+	})
+}
+
+// accountEditorRoles returns the IDs of the built-in roles of the account allowed to manage automations,
+// read once per sync.
+func (a *automationRuleBuilder) accountEditorRoles(ctx context.Context, account *account) ([]int64, error) {
+	return syncCached(account, "automation_editor_roles", func() ([]int64, error) {
+		roles, err := account.client.ListAllRoles(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var roleIDs []int64
+		for _, role := range roles {
+			isAdminRole := role.Default && (role.Name == accountAdminRoleName || role.Name == adminRoleName)
+			if isAdminRole && account.filters.roleIncluded(role.Name) {
+				roleIDs = append(roleIDs, role.ID)
+			}
+		}
+
+		return roleIDs, nil
+	})
+}
+
+func newAutomationRuleBuilder(accounts *accountSet) *automationRuleBuilder {
+	return &automationRuleBuilder{
+		resourceType: automationRuleResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoAutomationRuleResource - This function parses an automation rule into a Resource.
+// The description tells the automation type of the rule and whether it is active.
+func parseIntoAutomationRuleResource(rule *client.AutomationRule, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	status := "inactive"
+	if rule.Active {
+		status = "active"
+	}
+
+	description := fmt.Sprintf("%s rule, %s", automationTypeName(rule.AutomationType), status)
+	if rule.Summary != "" {
+		description += ". " + rule.Summary
+	}
+
+	return rs.NewResource(
+		rule.Name,
+		automationRuleResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
+
+// automationTypeName translates the automation type of a rule into the event that triggers it.
+func automationTypeName(automationType int64) string {
+	switch automationType {
+	case client.AutomationTypeTicketCreation:
+		return "Ticket creation"
+	case client.AutomationTypeTimeTriggers:
+		return "Time trigger"
+	case client.AutomationTypeTicketUpdates:
+		return "Ticket update"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,119 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAutomationAccount serves the roles, automation rules and scenarios of the acme account.
+// Scenarios are read from scenarios, so that tests can change them between syncs.
+func newTestAutomationAccount(t *testing.T, scenarios *string) *account {
+	t.Helper()
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/roles":
+			_, _ = w.Write([]byte(`[` +
+				`{"id":1,"name":"Account Administrator","default":true},` +
+				`{"id":2,"name":"Administrator","default":true},` +
+				`{"id":3,"name":"Agent","default":true},` +
+				`{"id":4,"name":"Administrator"}]`))
+		case "/api/v2/automations/1/rules":
+			_, _ = w.Write([]byte(`[{"id":11,"name":"Route VIP tickets","active":true,"created_by":42}]`))
+		case "/api/v2/automations/3/rules":
+			_, _ = w.Write([]byte(`[{"id":13,"name":"Reopen on reply"}]`))
+		case "/api/v2/automations/4/rules":
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v2/scenario_automations":
+			_, _ = w.Write([]byte(*scenarios))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	return &account{domain: "acme", client: c}
+}
+
+func TestAutomationRuleBuilder(t *testing.T) {
+	scenarios := `[]`
+	acme := newTestAutomationAccount(t, &scenarios)
+	rules := newAutomationRuleBuilder(newAccountSet(acme))
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "acme"}
+
+	resources, _, _, err := rules.List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "Route VIP tickets", resources[0].DisplayName)
+	assert.Equal(t, "Ticket creation rule, active", resources[0].Description)
+	assert.Equal(t, "Time trigger rule, inactive", resources[1].Description)
+
+	var principals []*v2.ResourceId
+	grants, _, _, err := rules.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	for _, g := range grants {
+		principals = append(principals, g.Principal.Id)
+	}
+	assert.Equal(t, []*v2.ResourceId{
+		{ResourceType: roleResourceType.Id, Resource: "1"},
+		{ResourceType: roleResourceType.Id, Resource: "2"},
+		{ResourceType: userResourceType.Id, Resource: "42"},
+	}, principals)
+
+	// A rule without a known creator is only granted to the administrator roles.
+	grants, _, _, err = rules.Grants(ctx, resources[1], &pagination.Token{})
+	require.NoError(t, err)
+	assert.Len(t, grants, 2)
+}
+
+func TestScenarioAutomationBuilder(t *testing.T) {
+	scenarios := `[{"id":21,"name":"Escalate","visibility":0,"created_by":42},` +
+		`{"id":22,"name":"Close as spam","visibility":1,"agent_ids":[42,43]}]`
+	acme := newTestAutomationAccount(t, &scenarios)
+	builder := newScenarioAutomationBuilder(newAccountSet(acme))
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "acme"}
+
+	resources, _, _, err := builder.List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	grants, _, _, err := builder.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, "scenario_automation:21:creator", grants[0].Entitlement.Id)
+	assert.Equal(t, &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "42"}, grants[0].Principal.Id)
+
+	grants, _, _, err = builder.Grants(ctx, resources[1], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, "scenario_automation:22:runner", grants[1].Entitlement.Id)
+	assert.Equal(t, &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "43"}, grants[1].Principal.Id)
+
+	// Scenarios are read once per sync: a change shows at the next sync only.
+	scenarios = `[{"id":21,"name":"Escalate","visibility":1,"agent_ids":[44],"created_by":42}]`
+
+	grants, _, _, err = builder.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	assert.Len(t, grants, 1)
+
+	acme.startSync()
+	grants, _, _, err = builder.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	assert.Len(t, grants, 2)
+	grants, _, _, err = builder.Grants(ctx, resources[1], &pagination.Token{})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}
@@ -0,0 +1,212 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+)
+
+// BulkAgent is one agent of a bulk creation. Roles and groups are given by name or by Freshdesk ID;
+// LicenseType and TicketScope take the values of CreateAccount and default to a full-time agent with
+// global access.
+type BulkAgent struct {
+	Email       string
+	Name        string
+	LicenseType string
+	TicketScope string
+	Roles       []string
+	Groups      []string
+}
+
+// BulkAgentResult is the outcome of the creation of one BulkAgent.
+type BulkAgentResult struct {
+	Email   string   `json:"email"`
+	AgentID int64    `json:"agent_id,omitempty"`
+	Success bool     `json:"success"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// BulkCreateAgents creates agents in one Freshdesk background job and waits for it to finish. The account
+// is picked by domain, the first one by default. Every agent is validated and the seats checked before
+// anything is sent, so either the job is submitted for all of them or an error is returned.
+func (d *Connector) BulkCreateAgents(ctx context.Context, domain string, agents []BulkAgent, backoff client.JobBackoff) ([]BulkAgentResult, error) {
+	if len(agents) == 0 {
+		return nil, nil
+	}
+
+	account := d.accounts.accounts[0]
+	if domain != "" {
+		var ok bool
+		account, ok = d.accounts.byDomain[domain]
+		if !ok {
+			return nil, fmt.Errorf("baton-freshdesk: unknown account %s", domain)
+		}
+	}
+
+	requests, err := account.bulkAgentRequests(ctx, agents)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make(map[string]int64)
+	for _, request := range requests {
+		seats[licenseType(&client.Agent{Occasional: request.Occasional})]++
+	}
+
+	err = account.checkSeatsAvailable(ctx, seats)
+	if err != nil {
+		return nil, err
+	}
+
+	jobReference, _, err := account.client.CreateAgents(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := account.client.WaitForJob(ctx, jobReference.JobID, backoff)
+	if err != nil {
+		return nil, err
+	}
+
+	return bulkAgentResults(agents, job), nil
+}
+
+// bulkAgentRequests validates the agents of a bulk creation and resolves their roles and groups.
+func (a *account) bulkAgentRequests(ctx context.Context, agents []BulkAgent) ([]client.CreateAgentRequest, error) {
+	roleIDs, groupIDs, err := a.roleAndGroupIDsByName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]client.CreateAgentRequest, 0, len(agents))
+	seen := make(map[string]bool)
+	for i, agent := range agents {
+		if agent.Email == "" {
+			return nil, fmt.Errorf("baton-freshdesk: agent %d has no email", i+1)
+		}
+
+		email := strings.ToLower(agent.Email)
+		if seen[email] {
+			return nil, fmt.Errorf("baton-freshdesk: agent %s is listed more than once", agent.Email)
+		}
+		seen[email] = true
+
+		if agent.LicenseType != "" && agent.LicenseType != seatFullTime && agent.LicenseType != seatOccasional {
+			return nil, fmt.Errorf("baton-freshdesk: unknown license type %s for agent %s, expected %s or %s",
+				agent.LicenseType, agent.Email, seatFullTime, seatOccasional)
+		}
+
+		ticketScope, err := ticketScopeValue(agent.TicketScope)
+		if err != nil {
+			return nil, fmt.Errorf("%w (agent %s)", err, agent.Email)
+		}
+
+		request := client.CreateAgentRequest{
+			Email:       agent.Email,
+			Name:        agent.Name,
+			TicketScope: ticketScope,
+			Occasional:  agent.LicenseType == seatOccasional,
+		}
+
+		request.RoleIDs, err = resolveNamedIDs(agent.Roles, roleIDs, "role", agent.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		request.GroupIDs, err = resolveNamedIDs(agent.Groups, groupIDs, "group", agent.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		rv = append(rv, request)
+	}
+
+	return rv, nil
+}
+
+// roleAndGroupIDsByName maps the lowercase names of the roles and groups of the account to their IDs.
+func (a *account) roleAndGroupIDsByName(ctx context.Context) (map[string]int64, map[string]int64, error) {
+	roles, err := a.client.ListAllRoles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roleIDs := make(map[string]int64)
+	for _, role := range roles {
+		roleIDs[strings.ToLower(role.Name)] = role.ID
+	}
+
+	groups, err := a.client.ListAllGroups(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groupIDs := make(map[string]int64)
+	for _, group := range groups {
+		groupIDs[strings.ToLower(group.Name)] = group.ID
+	}
+
+	return roleIDs, groupIDs, nil
+}
+
+// resolveNamedIDs turns role or group names, matched case-insensitively, or numeric IDs into Freshdesk IDs.
+func resolveNamedIDs(values []string, idsByName map[string]int64, kind, email string) ([]int64, error) {
+	var rv []int64
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if id, ok := idsByName[strings.ToLower(value)]; ok {
+			rv = append(rv, id)
+			continue
+		}
+
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("baton-freshdesk: unknown %s %s for agent %s", kind, value, email)
+		}
+		rv = append(rv, id)
+	}
+
+	return rv, nil
+}
+
+// bulkAgentResults matches the records of a finished job with the agents submitted. Records are matched
+// by email when Freshdesk reports it and by position otherwise. Agents without a record failed with the job.
+func bulkAgentResults(agents []BulkAgent, job *client.Job) []BulkAgentResult {
+	byEmail := make(map[string]*client.JobRecord)
+	for i := range job.Data {
+		if job.Data[i].Email != "" {
+			byEmail[strings.ToLower(job.Data[i].Email)] = &job.Data[i]
+		}
+	}
+
+	rv := make([]BulkAgentResult, 0, len(agents))
+	for i, agent := range agents {
+		record, ok := byEmail[strings.ToLower(agent.Email)]
+		if !ok && len(byEmail) == 0 && i < len(job.Data) {
+			record, ok = &job.Data[i], true
+		}
+
+		result := BulkAgentResult{Email: agent.Email}
+		if !ok {
+			result.Errors = []string{fmt.Sprintf("no result reported, the job ended %s", job.Status)}
+			rv = append(rv, result)
+			continue
+		}
+
+		result.Success = record.Success
+		result.Errors = record.ErrorMessages()
+		if record.Success {
+			result.AgentID = record.ID
+		}
+		rv = append(rv, result)
+	}
+
+	return rv
+}
@@ -0,0 +1,39 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveNamedIDs(t *testing.T) {
+	ids := map[string]int64{"supervisor": 3, "tier 1": 10}
+
+	rv, err := resolveNamedIDs([]string{"Supervisor", " Tier 1", "42", ""}, ids, "role", "jane@acme.com")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 10, 42}, rv)
+
+	_, err = resolveNamedIDs([]string{"Billing"}, ids, "group", "jane@acme.com")
+	require.EqualError(t, err, "baton-freshdesk: unknown group Billing for agent jane@acme.com")
+}
+
+func TestBulkAgentResults(t *testing.T) {
+	agents := []BulkAgent{{Email: "jane@acme.com"}, {Email: "Joe@acme.com"}, {Email: "ann@acme.com"}}
+	job := &client.Job{
+		Status: client.JobStatusCompleted,
+		Data: []client.JobRecord{
+			{Email: "joe@acme.com", Success: false, Errors: json.RawMessage(`"Seats exhausted"`)},
+			{ID: 7, Email: "jane@acme.com", Success: true},
+		},
+	}
+
+	results := bulkAgentResults(agents, job)
+	require.Len(t, results, 3)
+	assert.Equal(t, BulkAgentResult{Email: "jane@acme.com", AgentID: 7, Success: true}, results[0])
+	assert.Equal(t, BulkAgentResult{Email: "Joe@acme.com", Errors: []string{"Seats exhausted"}}, results[1])
+	assert.False(t, results[2].Success)
+	assert.NotEmpty(t, results[2].Errors)
+}
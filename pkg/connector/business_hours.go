@@ -0,0 +1,197 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// businessHourScheduleEntitlement links business hours to the groups whose tickets follow them.
+const businessHourScheduleEntitlement = "schedule"
+
+type businessHourBuilder struct {
+	resourceType   *v2.ResourceType
+	accounts       *accountSet
+	grantsPageSize int
+}
+
+func (b *businessHourBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return b.resourceType
+}
+
+// List returns the business hours calendars of the account.
+func (b *businessHourBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := b.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, businessHourResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	businessHours, nextPageToken, annotation, err := account.client.ListBusinessHours(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, businessHour := range businessHours {
+		businessHourCopy := businessHour
+		businessHourResource, err := parseIntoBusinessHourResource(&businessHourCopy, b.accounts.resourceID(account, businessHour.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, businessHourResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (b *businessHourBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		businessHourScheduleEntitlement,
+		entitlement.WithGrantableTo(groupResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Schedule", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Groups whose tickets follow the %s business hours", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants pages over the groups of the account and links the business hours to the groups following them.
+// Groups without business hours of their own follow the default ones. The grants only mirror the group
+// settings, so they are immutable.
+func (b *businessHourBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, businessHourID, err := b.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	defaultID, err := b.defaultBusinessHourID(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, groupResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	groups, nextPageToken, annotation, err := account.client.ListGroups(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: b.grantsPageSize,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, group := range groups {
+		groupBusinessHourID := group.BusinessHourID
+		if groupBusinessHourID == 0 {
+			groupBusinessHourID = defaultID
+		}
+
+		if groupBusinessHourID != businessHourID || !account.filters.groupIncluded(group.Name) {
+			continue
+		}
+
+		rv = append(rv, grant.NewGrant(
+			resource,
+			businessHourScheduleEntitlement,
+			&v2.ResourceId{
+				ResourceType: groupResourceType.Id,
+				Resource:     b.accounts.resourceID(account, group.ID),
+			},
+			grant.WithAnnotation(&v2.GrantImmutable{}),
+		))
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+// defaultBusinessHourID returns the ID of the default business hours of the account, or 0 if there is none.
+// It is read once per sync.
+func (b *businessHourBuilder) defaultBusinessHourID(ctx context.Context, account *account) (int64, error) {
+	return syncCached(account, "default_business_hour", func() (int64, error) {
+		businessHours, err := account.client.ListAllBusinessHours(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, businessHour := range businessHours {
+			if businessHour.IsDefault {
+				return businessHour.ID, nil
+			}
+		}
+
+		return 0, nil
+	})
+}
+
+func newBusinessHourBuilder(accounts *accountSet, grantsPageSize int) *businessHourBuilder {
+	return &businessHourBuilder{
+		resourceType:   businessHourResourceType,
+		accounts:       accounts,
+		grantsPageSize: grantsPageSize,
+	}
+}
+
+// parseIntoBusinessHourResource - This function parses a business hours calendar into a Resource.
+func parseIntoBusinessHourResource(businessHour *client.BusinessHour, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := fmt.Sprintf("Time zone %s", businessHour.TimeZone)
+	if businessHour.IsDefault {
+		description = "Default business hours, time zone " + businessHour.TimeZone
+	}
+	if businessHour.Description != "" {
+		description += ". " + businessHour.Description
+	}
+
+	return rs.NewResource(
+		businessHour.Name,
+		businessHourResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
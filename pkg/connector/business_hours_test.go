@@ -0,0 +1,65 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessHourBuilder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/business_hours":
+			_, _ = w.Write([]byte(`[` +
+				`{"id":1,"name":"Office hours","is_default":true,"time_zone":"Eastern Time (US & Canada)"},` +
+				`{"id":2,"name":"Around the clock","time_zone":"UTC","description":"Premium support"}]`))
+		case "/api/v2/groups":
+			_, _ = w.Write([]byte(`[{"id":10,"name":"Billing"},{"id":11,"name":"Premium","business_hour_id":2},` +
+				`{"id":12,"name":"Sales","business_hour_id":1}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	builder := newBusinessHourBuilder(newAccountSet(&account{domain: "acme", client: c}), client.ItemsPerPage)
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "acme"}
+
+	resources, _, _, err := builder.List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "Default business hours, time zone Eastern Time (US & Canada)", resources[0].Description)
+	assert.Equal(t, "Time zone UTC. Premium support", resources[1].Description)
+
+	// Groups without business hours of their own follow the default ones.
+	tests := map[*v2.Resource][]string{
+		resources[0]: {"10", "12"},
+		resources[1]: {"11"},
+	}
+	for resource, groupIDs := range tests {
+		grants, _, _, err := builder.Grants(ctx, resource, &pagination.Token{})
+		require.NoError(t, err)
+
+		var principals []string
+		for _, g := range grants {
+			assert.Equal(t, groupResourceType.Id, g.Principal.Id.ResourceType)
+			principals = append(principals, g.Principal.Id.Resource)
+
+			annos := annotations.Annotations(g.Annotations)
+			assert.True(t, annos.Contains(&v2.GrantImmutable{}))
+		}
+		assert.Equal(t, groupIDs, principals, resource.DisplayName)
+	}
+}
@@ -0,0 +1,141 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type cannedResponseFolderBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (c *cannedResponseFolderBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return c.resourceType
+}
+
+// List returns the canned response folders of the account.
+func (c *cannedResponseFolderBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := c.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, cannedResponseFolderResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	folders, nextPageToken, annotation, err := account.client.ListCannedResponseFolders(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, folder := range folders {
+		folderCopy := folder
+		folderResource, err := parseIntoCannedResponseFolderResource(&folderCopy, c.accounts.resourceID(account, folder.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, folderResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (c *cannedResponseFolderBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		sharedItemViewerEntitlement,
+		entitlement.WithGrantableTo(userResourceType, groupResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Canned Responses Viewer", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Can use some of the canned responses of the %s folder", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants returns who can see at least one response of the folder: the owners of personal responses and the
+// groups responses are shared with. Responses visible to every agent have no grants.
+func (c *cannedResponseFolderBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, folderID, err := c.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	responses, err := account.client.ListAllCannedResponses(ctx, folderID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	granted := make(map[string]bool)
+	for _, response := range responses {
+		grants, err := visibilityGrants(ctx, c.accounts, account, resource, sharedItemViewerEntitlement, response.Visibility, response.GroupIDs, []int64{response.UserID})
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		for _, g := range grants {
+			principal := g.Principal.Id.ResourceType + ":" + g.Principal.Id.Resource
+			if granted[principal] {
+				continue
+			}
+			granted[principal] = true
+			rv = append(rv, g)
+		}
+	}
+
+	return rv, "", nil, nil
+}
+
+func newCannedResponseFolderBuilder(accounts *accountSet) *cannedResponseFolderBuilder {
+	return &cannedResponseFolderBuilder{
+		resourceType: cannedResponseFolderResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoCannedResponseFolderResource - This function parses a canned response folder into a Resource.
+func parseIntoCannedResponseFolderResource(folder *client.CannedResponseFolder, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := fmt.Sprintf("%d canned responses", folder.ResponsesCount)
+	if folder.Personal {
+		description = fmt.Sprintf("Personal folder, %d canned responses", folder.ResponsesCount)
+	}
+
+	return rs.NewResource(
+		folder.Name,
+		cannedResponseFolderResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
@@ -0,0 +1,95 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCannedResponseFolderBuilder(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/canned_response_folders":
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[{"id":2,"name":"Personal","personal":true,"responses_count":1}]`))
+				return
+			}
+			w.Header().Set("Link", `</api/v2/canned_response_folders?per_page=1&page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":1,"name":"Billing","responses_count":4}]`))
+		case "/api/v2/canned_response_folders/1/responses":
+			// The responses of a folder span two pages, and grant the same agent and group more than once.
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[` +
+					`{"id":13,"visibility":1,"user_id":42},` +
+					`{"id":14,"visibility":2,"group_ids":[3,4],"user_id":43}]`))
+				return
+			}
+			w.Header().Set("Link", `</api/v2/canned_response_folders/1/responses?per_page=100&page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[` +
+				`{"id":11,"visibility":0,"user_id":41},` +
+				`{"id":12,"visibility":1,"user_id":42,"group_ids":[5]},` +
+				`{"id":15,"visibility":2,"group_ids":[3],"user_id":44}]`))
+		case "/api/v2/canned_response_folders/2/responses":
+			_, _ = w.Write([]byte(`[{"id":21,"visibility":1,"user_id":45}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	builder := newCannedResponseFolderBuilder(newAccountSet(&account{domain: "acme", client: c}))
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "acme"}
+
+	resources, nextToken, _, err := builder.List(ctx, parent, &pagination.Token{Size: 1})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "Billing", resources[0].DisplayName)
+	require.NotEmpty(t, nextToken)
+
+	personal, nextToken, _, err := builder.List(ctx, parent, &pagination.Token{Size: 1, Token: nextToken})
+	require.NoError(t, err)
+	require.Len(t, personal, 1)
+	assert.Equal(t, "Personal folder, 1 canned responses", personal[0].Description)
+	assert.Empty(t, nextToken)
+
+	// Every agent and group seeing at least one response of the folder is granted once, whatever the page.
+	grants, _, _, err := builder.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	var principals []*v2.ResourceId
+	for _, g := range grants {
+		assert.Equal(t, "canned_response_folder:1:viewer", g.Entitlement.Id)
+		principals = append(principals, g.Principal.Id)
+	}
+	assert.Equal(t, []*v2.ResourceId{
+		{ResourceType: userResourceType.Id, Resource: "42"},
+		{ResourceType: groupResourceType.Id, Resource: "3"},
+		{ResourceType: groupResourceType.Id, Resource: "4"},
+	}, principals)
+
+	expandable := &v2.GrantExpandable{}
+	annos := annotations.Annotations(grants[1].Annotations)
+	ok, err := annos.Pick(expandable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"group:3:member"}, expandable.EntitlementIds)
+
+	// A personal response is only granted to the agent owning it.
+	grants, _, _, err = builder.Grants(ctx, personal[0], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "45"}, grants[0].Principal.Id)
+}
@@ -0,0 +1,144 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// companyMemberEntitlement is held by the contacts whose company is the company.
+const companyMemberEntitlement = "member"
+
+type companyBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+	syncContacts bool
+}
+
+func (c *companyBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return c.resourceType
+}
+
+// List returns the customer companies of the account. They are synced as the principals
+// knowledge-base folders can be restricted to, along with their contacts.
+func (c *companyBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := c.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, companyResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	companies, nextPageToken, annotation, err := account.client.ListCompanies(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, company := range companies {
+		companyCopy := company
+		companyResource, err := parseIntoCompanyResource(&companyCopy, c.accounts.resourceID(account, company.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, companyResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (c *companyBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		companyMemberEntitlement,
+		entitlement.WithGrantableTo(contactResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Company Member", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Customer contact of the %s company", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants returns the contacts of the company, looked up by company_id with the search API. Companies have
+// no grants when contacts are not synced.
+func (c *companyBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+	if !c.syncContacts {
+		return nil, "", nil, nil
+	}
+
+	account, companyID, err := c.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	contacts, err := account.client.SearchContacts(ctx, client.SearchEq("company_id", companyID))
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, contact := range contacts {
+		rv = append(rv, grant.NewGrant(resource, companyMemberEntitlement, &v2.ResourceId{
+			ResourceType: contactResourceType.Id,
+			Resource:     c.accounts.resourceID(account, contact.ID),
+		}))
+	}
+
+	return rv, "", nil, nil
+}
+
+func newCompanyBuilder(accounts *accountSet, syncContacts bool) *companyBuilder {
+	return &companyBuilder{
+		resourceType: companyResourceType,
+		accounts:     accounts,
+		syncContacts: syncContacts,
+	}
+}
+
+// parseIntoCompanyResource - This function parses a Freshdesk company into a Group Resource.
+func parseIntoCompanyResource(company *client.Company, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"company_id":   company.ID,
+		"company_name": company.Name,
+		"domains":      strings.Join(company.Domains, ","),
+	}
+
+	return rs.NewGroupResource(
+		company.Name,
+		companyResourceType,
+		resourceID,
+		[]rs.GroupTraitOption{rs.WithGroupProfile(profile)},
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(company.Description),
+	)
+}
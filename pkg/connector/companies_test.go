@@ -0,0 +1,42 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompanyGrantsSearchContacts(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path != "/api/v2/search/contacts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query = r.URL.Query().Get("query")
+		_, _ = w.Write([]byte(`{"total":2,"results":[{"id":42,"company_id":12},{"id":43,"company_id":12}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	companies := newCompanyBuilder(newAccountSet(&account{domain: "acme", client: c}), true)
+	company := &v2.Resource{Id: &v2.ResourceId{ResourceType: companyResourceType.Id, Resource: "12"}}
+
+	grants, _, _, err := companies.Grants(ctx, company, &pagination.Token{})
+	require.NoError(t, err)
+	assert.Equal(t, `"company_id:12"`, query)
+	require.Len(t, grants, 2)
+	assert.Equal(t, "company:12:member", grants[0].Entitlement.Id)
+	assert.Equal(t, &v2.ResourceId{ResourceType: contactResourceType.Id, Resource: "42"}, grants[0].Principal.Id)
+	assert.Equal(t, &v2.ResourceId{ResourceType: contactResourceType.Id, Resource: "43"}, grants[1].Principal.Id)
+}
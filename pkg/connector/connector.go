@@ -2,6 +2,7 @@ package connector
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/conductorone/baton-freshdesk/pkg/client"
@@ -9,18 +10,111 @@ import (
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/metrics"
 )
 
 type Connector struct {
-	client *client.FreshdeskClient
+	accounts                *accountSet
+	grantsPageSize          int
+	agentDetailsConcurrency int
+	seatLimits              []SeatLimit
+	filters                 *Filters
+	agentDefaults           AgentDefaults
+	syncContacts            bool
+	logHTTPBodies           bool
+	cassette                *client.Cassette
+	metricsHandler          metrics.Handler
+}
+
+type Option func(c *Connector)
+
+// WithAgentDetailsConcurrency sets how many agent detail requests may be in flight at once.
+func WithAgentDetailsConcurrency(concurrency int) Option {
+	return func(c *Connector) {
+		c.agentDetailsConcurrency = concurrency
+	}
+}
+
+// WithGrantsPageSize sets how many agents are inspected per page when listing role and group grants.
+func WithGrantsPageSize(pageSize int) Option {
+	return func(c *Connector) {
+		c.grantsPageSize = pageSize
+	}
+}
+
+// WithSeatLimits sets the number of seats purchased per account and seat category.
+func WithSeatLimits(limits ...SeatLimit) Option {
+	return func(c *Connector) {
+		c.seatLimits = append(c.seatLimits, limits...)
+	}
+}
+
+// WithFilters narrows down the agents, groups and roles synced.
+func WithFilters(filters Filters) Option {
+	return func(c *Connector) {
+		c.filters = &filters
+	}
+}
+
+// WithAgentDefaults sets the roles and groups given to the contacts converted into agents.
+func WithAgentDefaults(defaults AgentDefaults) Option {
+	return func(c *Connector) {
+		c.agentDefaults = defaults
+	}
+}
+
+// WithContactSync also syncs the customer contacts of every account, along with their company memberships.
+// Only synced contacts can be sent invites, as no contact resource exists otherwise. Contacts are converted
+// into agents by creating the user account with their email, whether they are synced or not.
+func WithContactSync(enabled bool) Option {
+	return func(c *Connector) {
+		c.syncContacts = enabled
+	}
+}
+
+// WithHTTPBodyLogging also logs the bodies of the Freshdesk requests and responses at debug level,
+// with their personal data masked.
+func WithHTTPBodyLogging(enabled bool) Option {
+	return func(c *Connector) {
+		c.logHTTPBodies = enabled
+	}
+}
+
+// WithCassette records the Freshdesk requests of every account to the cassette, or replays them from it.
+func WithCassette(cassette *client.Cassette) Option {
+	return func(c *Connector) {
+		c.cassette = cassette
+	}
+}
+
+// WithMetricsHandler reports the Freshdesk API usage of every account through handler: requests and their
+// duration by endpoint, method and status, and the requests left in the rate limit window.
+func WithMetricsHandler(handler metrics.Handler) Option {
+	return func(c *Connector) {
+		c.metricsHandler = handler
+	}
 }
 
 // ResourceSyncers returns a ResourceSyncer for each resource type that should be synced from the upstream service.
 func (d *Connector) ResourceSyncers(_ context.Context) []connectorbuilder.ResourceSyncer {
 	return []connectorbuilder.ResourceSyncer{
-		newUserBuilder(d.client),
-		newRoleBuilder(d.client),
-		newGroupBuilder(d.client),
+		newAccountBuilder(d.accounts, d.grantsPageSize),
+		newUserBuilder(d.accounts, d.agentDefaults),
+		newContactBuilder(d.accounts, d.syncContacts),
+		newRoleBuilder(d.accounts, d.grantsPageSize),
+		newGroupBuilder(d.accounts, d.grantsPageSize),
+		newCompanyBuilder(d.accounts, d.syncContacts),
+		newContactSegmentBuilder(d.accounts),
+		newProductBuilder(d.accounts),
+		newEmailConfigBuilder(d.accounts),
+		newCannedResponseFolderBuilder(d.accounts),
+		newTicketTemplateBuilder(d.accounts),
+		newScenarioAutomationBuilder(d.accounts),
+		newAutomationRuleBuilder(d.accounts),
+		newBusinessHourBuilder(d.accounts, d.grantsPageSize),
+		newSlaPolicyBuilder(d.accounts),
+		newSolutionCategoryBuilder(d.accounts),
+		newSolutionFolderBuilder(d.accounts),
 	}
 }
 
@@ -44,19 +138,81 @@ func (d *Connector) Validate(ctx context.Context) (annotations.Annotations, erro
 	return nil, nil
 }
 
-// New returns a new instance of the connector.
-func New(ctx context.Context, domain, apiKey string) (*Connector, error) {
-	freshdeskClient, err := client.New(
-		ctx,
-		client.WithDomain(domain),
-		client.WithBearerToken(apiKey),
-	)
+// New returns a new instance of the connector, syncing every given Freshdesk account.
+func New(ctx context.Context, accountConfigs []AccountConfig, opts ...Option) (*Connector, error) {
+	if len(accountConfigs) == 0 {
+		return nil, fmt.Errorf("baton-freshdesk: at least one Freshdesk account is required")
+	}
 
-	if err != nil {
-		return nil, err
+	c := &Connector{
+		grantsPageSize:          client.ItemsPerPage,
+		agentDetailsConcurrency: defaultAgentDetailsConcurrency,
 	}
 
-	return &Connector{
-		client: freshdeskClient,
-	}, nil
+	for _, o := range opts {
+		o(c)
+	}
+
+	if c.filters != nil {
+		err := c.filters.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var accounts []*account
+	domains := make(map[string]bool)
+	for _, accountConfig := range accountConfigs {
+		if domains[accountConfig.Domain] {
+			return nil, fmt.Errorf("baton-freshdesk: account %s is configured more than once", accountConfig.Domain)
+		}
+		domains[accountConfig.Domain] = true
+
+		credential := client.WithBearerToken(accountConfig.APIKey)
+		if accountConfig.APIKeySource != nil {
+			credential = client.WithAPIKeySource(accountConfig.APIKeySource)
+		}
+
+		freshdeskClient, err := client.New(
+			ctx,
+			client.WithDomain(accountConfig.Domain),
+			client.WithBodyLogging(c.logHTTPBodies),
+			client.WithCassette(c.cassette),
+			client.WithMetricsHandler(c.metricsHandler),
+			credential,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, &account{
+			domain:       accountConfig.Domain,
+			client:       freshdeskClient,
+			agentDetails: newAgentDetailFetcher(freshdeskClient, c.agentDetailsConcurrency),
+			seatLimits:   seatLimitsFor(accountConfig.Domain, c.seatLimits),
+			filters:      c.filters,
+		})
+	}
+	c.accounts = newAccountSet(accounts...)
+
+	return c, nil
+}
+
+// seatLimitsFor returns the seat limits of an account by category. Limits set for the account
+// take precedence over the ones set for every account.
+func seatLimitsFor(domain string, limits []SeatLimit) map[string]int64 {
+	rv := make(map[string]int64)
+	for _, limit := range limits {
+		if limit.Domain == "" {
+			rv[limit.Category] = limit.Limit
+		}
+	}
+
+	for _, limit := range limits {
+		if limit.Domain == domain {
+			rv[limit.Category] = limit.Limit
+		}
+	}
+
+	return rv
 }
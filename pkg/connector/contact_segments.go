@@ -0,0 +1,101 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type contactSegmentBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (c *contactSegmentBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return c.resourceType
+}
+
+// List returns the contact segments of the account. They are synced as the principals
+// knowledge-base folders can be restricted to.
+func (c *contactSegmentBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := c.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, contactSegmentResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	segments, nextPageToken, annotation, err := account.client.ListContactSegments(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, segment := range segments {
+		segmentCopy := segment
+		segmentResource, err := parseIntoContactSegmentResource(&segmentCopy, c.accounts.resourceID(account, segment.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, segmentResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for contact segments, which are only synced as principals.
+func (c *contactSegmentBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for contact segments since they don't have any entitlements.
+func (c *contactSegmentBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newContactSegmentBuilder(accounts *accountSet) *contactSegmentBuilder {
+	return &contactSegmentBuilder{
+		resourceType: contactSegmentResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoContactSegmentResource - This function parses a Freshdesk contact segment into a Group Resource.
+func parseIntoContactSegmentResource(segment *client.ContactSegment, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"segment_id":   segment.ID,
+		"segment_name": segment.Name,
+	}
+
+	return rs.NewGroupResource(
+		segment.Name,
+		contactSegmentResourceType,
+		resourceID,
+		[]rs.GroupTraitOption{rs.WithGroupProfile(profile)},
+		rs.WithParentResourceID(parentResourceID),
+	)
+}
@@ -0,0 +1,169 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type contactBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+	syncContacts bool
+}
+
+func (c *contactBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return c.resourceType
+}
+
+// List returns, when contact syncing is enabled, the customer contacts of the account, who log in to the
+// support portal. Agents are not listed again as contacts: they are users, and a contact is converted into
+// an agent by creating the user account with its email.
+func (c *contactBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil || !c.syncContacts {
+		return nil, "", nil, nil
+	}
+
+	account, err := c.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, contactResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	contacts, nextPageToken, annotation, err := account.client.ListContacts(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, contact := range contacts {
+		contactCopy := contact
+		contactResource, err := parseIntoContactResource(&contactCopy, c.accounts.resourceID(account, contact.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, contactResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+// Entitlements always returns an empty slice for contacts.
+func (c *contactBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for contacts.
+func (c *contactBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Rotate sends the contact the Freshdesk activation invite, or sends it again, so they can set a
+// password and log in to the support portal. Freshdesk emails the invite itself, so nothing is returned.
+func (c *contactBuilder) Rotate(
+	ctx context.Context,
+	resourceId *v2.ResourceId,
+	_ *v2.CredentialOptions,
+) ([]*v2.PlaintextData, annotations.Annotations, error) {
+	if resourceId.ResourceType != contactResourceType.Id {
+		return nil, nil, fmt.Errorf("baton-freshdesk: only contacts can be sent an invite, got a %s", resourceId.ResourceType)
+	}
+
+	account, contactID, err := c.accounts.parseResourceID(resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	annotation, err := account.client.SendContactInvite(ctx, contactID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, annotation, nil
+}
+
+// RotateCapabilityDetails reports that no credential is returned: the contact sets their password from the invite.
+func (c *contactBuilder) RotateCapabilityDetails(_ context.Context) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return &v2.CredentialDetailsCredentialRotation{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+	}, nil, nil
+}
+
+func newContactBuilder(accounts *accountSet, syncContacts bool) *contactBuilder {
+	return &contactBuilder{
+		resourceType: contactResourceType,
+		accounts:     accounts,
+		syncContacts: syncContacts,
+	}
+}
+
+// parseIntoContactResource - This function parses a Freshdesk customer contact into a User Resource.
+// A contact becomes active once they accept their invite; until then they can't log in to the portal.
+// Blocked contacts are disabled and soft-deleted ones deleted.
+func parseIntoContactResource(contact *client.Contact, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"contact_id": contact.ID,
+		"login":      contact.Email,
+		"email":      contact.Email,
+		"name":       contact.Name,
+		"job_title":  contact.JobTitle,
+		"company_id": contact.CompanyID,
+		"active":     contact.Active,
+		"blocked":    contact.Blocked,
+		"deleted":    contact.Deleted,
+	}
+
+	userTraits := []rs.UserTraitOption{
+		rs.WithUserProfile(profile),
+		rs.WithStatus(contactStatus(contact)),
+	}
+
+	if contact.Email != "" {
+		userTraits = append(userTraits,
+			rs.WithUserLogin(contact.Email),
+			rs.WithEmail(contact.Email, true),
+		)
+	}
+
+	if !contact.LastLoginAt.IsZero() {
+		userTraits = append(userTraits, rs.WithLastLogin(contact.LastLoginAt))
+	}
+
+	displayName := contact.Name
+	if displayName == "" {
+		displayName = contact.Email
+	}
+
+	return rs.NewUserResource(
+		displayName,
+		contactResourceType,
+		resourceID,
+		userTraits,
+		rs.WithParentResourceID(parentResourceID),
+	)
+}
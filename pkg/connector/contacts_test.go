@@ -0,0 +1,131 @@
+package connector
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactRotateSendsInvite(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.Method + " " + r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	contacts := newContactBuilder(newTestAccountSet(c), true)
+	plaintexts, _, err := contacts.Rotate(ctx, &v2.ResourceId{ResourceType: contactResourceType.Id, Resource: "42"}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, plaintexts)
+	assert.Equal(t, "PUT /api/v2/contacts/42/send_invite", requested)
+
+	_, _, err = contacts.Rotate(ctx, &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "42"}, nil)
+	require.Error(t, err)
+}
+
+func TestContactAgentConversion(t *testing.T) {
+	var requested []string
+	var makeAgentBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/agents":
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v2/search/contacts":
+			_, _ = w.Write([]byte(`{"results":[{"id":42,"email":"jane@acme.com"}],"total":1}`))
+		case "/api/v2/roles":
+			_, _ = w.Write([]byte(`[{"id":3,"name":"Supervisor"}]`))
+		case "/api/v2/groups":
+			_, _ = w.Write([]byte(`[{"id":8,"name":"Billing"}]`))
+		case "/api/v2/contacts/42/make_agent":
+			body, _ := io.ReadAll(r.Body)
+			makeAgentBody = string(body)
+			_, _ = w.Write([]byte(`{"id":42,"contact":{"email":"jane@acme.com"}}`))
+		case "/api/v2/agents/42":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	// Contacts have no agent entitlement: they are converted by creating the user account with their email.
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: domain}
+	contact, err := parseIntoContactResource(&client.Contact{ID: 42, Email: "jane@acme.com"}, "42", parent)
+	require.NoError(t, err)
+	entitlements, _, _, err := newContactBuilder(newTestAccountSet(c), true).Entitlements(ctx, contact, nil)
+	require.NoError(t, err)
+	assert.Empty(t, entitlements)
+
+	users := newUserBuilder(newTestAccountSet(c), AgentDefaults{Roles: []string{"supervisor"}, Groups: []string{"Billing", "12"}})
+	result, _, _, err := users.CreateAccount(ctx, &v2.AccountInfo{
+		Emails: []*v2.AccountInfo_Email{{Address: "jane@acme.com", IsPrimary: true}},
+	}, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ticket_scope":1,"occasional":false,"role_ids":[3],"group_ids":[8,12]}`, makeAgentBody)
+
+	// The agent keeps the ID of the contact, and its user resource holds the agent entitlement.
+	success, ok := result.(*v2.CreateAccountResponse_SuccessResult)
+	require.True(t, ok)
+	assert.True(t, success.IsCreateAccountResult)
+	agent := success.Resource
+	assert.Equal(t, &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "42"}, agent.Id)
+
+	grants, _, _, err := users.Grants(ctx, agent, nil)
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, agent.Id, grants[0].Principal.Id)
+	assert.Equal(t, "user:42:agent", grants[0].Entitlement.Id)
+
+	annos, err := users.Grant(ctx, agent, grants[0].Entitlement)
+	require.NoError(t, err)
+	assert.True(t, annos.Contains(&v2.GrantAlreadyExists{}))
+
+	_, err = users.Revoke(ctx, grants[0])
+	require.NoError(t, err)
+	assert.Contains(t, requested, "DELETE /api/v2/agents/42")
+
+	// The agent entitlement of a user is only ever revoked from the user itself.
+	other, err := parseIntoUserResource(&client.Agent{ID: 43, Contact: client.Contact{Email: "john@acme.com"}}, "43", parent)
+	require.NoError(t, err)
+	_, err = users.Revoke(ctx, &v2.Grant{Entitlement: grants[0].Entitlement, Principal: other})
+	require.Error(t, err)
+}
+
+func TestContactsAreOnlyListedWhenSynced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: domain}
+	resources, nextToken, _, err := newContactBuilder(newTestAccountSet(c), false).List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+	assert.Empty(t, nextToken)
+
+	company := &v2.Resource{Id: &v2.ResourceId{ResourceType: companyResourceType.Id, Resource: "12"}}
+	grants, _, _, err := newCompanyBuilder(newTestAccountSet(c), false).Grants(ctx, company, &pagination.Token{})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}
@@ -0,0 +1,157 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// emailConfigReaderEntitlement is held by the agents who can read the tickets of a mailbox.
+const emailConfigReaderEntitlement = "reader"
+
+type emailConfigBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (e *emailConfigBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return e.resourceType
+}
+
+// List returns the support mailboxes of the account.
+func (e *emailConfigBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := e.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, emailConfigResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	emailConfigs, nextPageToken, annotation, err := account.client.ListEmailConfigs(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, emailConfig := range emailConfigs {
+		emailConfigCopy := emailConfig
+		emailConfigResource, err := parseIntoEmailConfigResource(&emailConfigCopy, e.accounts.resourceID(account, emailConfig.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, emailConfigResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (e *emailConfigBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		emailConfigReaderEntitlement,
+		entitlement.WithGrantableTo(groupResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Reader", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Can read the tickets received by %s, as a member of the group it routes to", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants grants the mailbox to the group owning it, expanded to the group members.
+// Mailboxes without a group route to the unassigned queue and have no grants. The mailboxes are read
+// once per sync and shared with the product builder; one created since has no grants until the next sync.
+func (e *emailConfigBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, emailConfigID, err := e.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	emailConfigs, err := accountEmailConfigs(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	index := slices.IndexFunc(emailConfigs, func(emailConfig client.EmailConfig) bool {
+		return emailConfig.ID == emailConfigID
+	})
+	if index < 0 {
+		return nil, "", nil, nil
+	}
+	emailConfig := emailConfigs[index]
+
+	inScope := false
+	if emailConfig.GroupID != 0 {
+		inScope, err = account.groupInScope(ctx, emailConfig.GroupID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	if inScope {
+		rv = append(rv, newGroupExpandedGrant(resource, emailConfigReaderEntitlement, &v2.ResourceId{
+			ResourceType: groupResourceType.Id,
+			Resource:     e.accounts.resourceID(account, emailConfig.GroupID),
+		}))
+	}
+
+	return rv, "", nil, nil
+}
+
+func newEmailConfigBuilder(accounts *accountSet) *emailConfigBuilder {
+	return &emailConfigBuilder{
+		resourceType: emailConfigResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoEmailConfigResource - This function parses a support mailbox into a Resource named after its address.
+func parseIntoEmailConfigResource(emailConfig *client.EmailConfig, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	displayName := emailConfig.ToEmail
+	if displayName == "" {
+		displayName = emailConfig.Name
+	}
+
+	description := emailConfig.Name
+	if !emailConfig.Active {
+		description = fmt.Sprintf("%s (inactive)", emailConfig.Name)
+	}
+
+	return rs.NewResource(
+		displayName,
+		emailConfigResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
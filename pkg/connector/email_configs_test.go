@@ -0,0 +1,71 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailConfigGrants(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	var emailConfigLists, otherRequests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/email_configs":
+			emailConfigLists.Add(1)
+			_, _ = w.Write([]byte(`[` +
+				`{"id":7,"to_email":"help@acme.com","product_id":1,"group_id":3},` +
+				`{"id":8,"to_email":"sales@acme.com"},` +
+				`{"id":9,"to_email":"contractors@acme.com","group_id":4}]`))
+		case "/api/v2/groups":
+			_, _ = w.Write([]byte(`[{"id":3,"name":"Support"},{"id":4,"name":"Contractors"}]`))
+		default:
+			otherRequests.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	acme := &account{domain: "acme", client: c, filters: &Filters{ExcludeGroups: []string{"Contractors"}}}
+	builder := newEmailConfigBuilder(newAccountSet(acme))
+	mailbox := func(id string) *v2.Resource {
+		return &v2.Resource{Id: &v2.ResourceId{ResourceType: emailConfigResourceType.Id, Resource: id}}
+	}
+
+	grants, _, _, err := builder.Grants(ctx, mailbox("7"), &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, "email_config:7:reader", grants[0].Entitlement.Id)
+	assert.Equal(t, &v2.ResourceId{ResourceType: groupResourceType.Id, Resource: "3"}, grants[0].Principal.Id)
+	expandable := &v2.GrantExpandable{}
+	annos := annotations.Annotations(grants[0].Annotations)
+	ok, err := annos.Pick(expandable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"group:3:member"}, expandable.EntitlementIds)
+
+	// Mailboxes without a group, routed to an excluded group, or gone since the listing have no grants.
+	for _, id := range []string{"8", "9", "10"} {
+		grants, _, _, err = builder.Grants(ctx, mailbox(id), &pagination.Token{})
+		require.NoError(t, err)
+		assert.Empty(t, grants, id)
+	}
+
+	// The mailboxes are listed once per sync rather than read one by one.
+	assert.Equal(t, int64(1), emailConfigLists.Load())
+	assert.Zero(t, otherRequests.Load())
+}
@@ -0,0 +1,157 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+)
+
+// AgentTypes lists the agent types an agent type filter accepts.
+var AgentTypes = []string{"support_agent", "field_agent", "collaborator"}
+
+// Filters narrows down the agents, groups and roles in scope. An include list keeps only the matching
+// items and an exclude list drops the matching ones; an item must pass both. Empty lists don't filter.
+// Email domains and role names are matched case-insensitively, group names against case-insensitive
+// shell patterns such as Sandbox-*.
+type Filters struct {
+	IncludeEmailDomains []string
+	ExcludeEmailDomains []string
+	IncludeAgentTypes   []string
+	ExcludeAgentTypes   []string
+	IncludeGroups       []string
+	ExcludeGroups       []string
+	IncludeRoles        []string
+	ExcludeRoles        []string
+}
+
+// Validate checks the agent types and the group name patterns.
+func (f *Filters) Validate() error {
+	for _, agentType := range append(slices.Clone(f.IncludeAgentTypes), f.ExcludeAgentTypes...) {
+		if !slices.Contains(AgentTypes, agentType) {
+			return fmt.Errorf("baton-freshdesk: unknown agent type %s, expected one of %s", agentType, strings.Join(AgentTypes, ", "))
+		}
+	}
+
+	for _, pattern := range append(slices.Clone(f.IncludeGroups), f.ExcludeGroups...) {
+		_, err := path.Match(strings.ToLower(pattern), "")
+		if err != nil {
+			return fmt.Errorf("baton-freshdesk: invalid group name pattern %s: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// filtersAgents reports whether some agents may be out of scope.
+func (f *Filters) filtersAgents() bool {
+	return f != nil && len(f.IncludeEmailDomains)+len(f.ExcludeEmailDomains)+len(f.IncludeAgentTypes)+len(f.ExcludeAgentTypes) > 0
+}
+
+// filtersGroups reports whether some groups may be out of scope.
+func (f *Filters) filtersGroups() bool {
+	return f != nil && len(f.IncludeGroups)+len(f.ExcludeGroups) > 0
+}
+
+func (f *Filters) agentIncluded(agent *client.Agent) bool {
+	if !f.filtersAgents() {
+		return true
+	}
+
+	_, emailDomain, _ := strings.Cut(agent.Contact.Email, "@")
+	equalDomain := func(domain string) bool {
+		return strings.EqualFold(strings.TrimPrefix(domain, "@"), emailDomain)
+	}
+	equalType := func(agentType string) bool {
+		return agentType == agent.Type
+	}
+
+	return included(f.IncludeEmailDomains, f.ExcludeEmailDomains, equalDomain) &&
+		included(f.IncludeAgentTypes, f.ExcludeAgentTypes, equalType)
+}
+
+func (f *Filters) groupIncluded(name string) bool {
+	if !f.filtersGroups() {
+		return true
+	}
+
+	return included(f.IncludeGroups, f.ExcludeGroups, func(pattern string) bool {
+		matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name))
+		return matched
+	})
+}
+
+func (f *Filters) roleIncluded(name string) bool {
+	if f == nil {
+		return true
+	}
+
+	return included(f.IncludeRoles, f.ExcludeRoles, func(roleName string) bool {
+		return strings.EqualFold(roleName, name)
+	})
+}
+
+// included reports whether an item matching the given entries passes an include and an exclude list.
+func included(include, exclude []string, matches func(string) bool) bool {
+	if len(include) > 0 && !slices.ContainsFunc(include, matches) {
+		return false
+	}
+
+	return !slices.ContainsFunc(exclude, matches)
+}
+
+// agentInScope reports whether the agent agentID of the account passes the filters. It is meant for
+// resources that only know the ID of an agent; the agents in scope are listed once per sync.
+func (a *account) agentInScope(ctx context.Context, agentID int64) (bool, error) {
+	if !a.filters.filtersAgents() {
+		return true, nil
+	}
+
+	scope, err := syncCached(a, "agent_scope", func() (map[int64]bool, error) {
+		agents, err := a.client.ListAllAgents(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		scope := make(map[int64]bool, len(agents))
+		for i := range agents {
+			scope[agents[i].ID] = a.filters.agentIncluded(&agents[i])
+		}
+
+		return scope, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return scope[agentID], nil
+}
+
+// groupInScope reports whether the group groupID of the account passes the filters, like agentInScope.
+func (a *account) groupInScope(ctx context.Context, groupID int64) (bool, error) {
+	if !a.filters.filtersGroups() {
+		return true, nil
+	}
+
+	scope, err := syncCached(a, "group_scope", func() (map[int64]bool, error) {
+		groups, err := a.client.ListAllGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		scope := make(map[int64]bool, len(groups))
+		for _, group := range groups {
+			scope[group.ID] = a.filters.groupIncluded(group.Name)
+		}
+
+		return scope, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return scope[groupID], nil
+}
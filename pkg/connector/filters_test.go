@@ -0,0 +1,82 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilters(t *testing.T) {
+	filters := &Filters{
+		IncludeEmailDomains: []string{"acme.com"},
+		ExcludeAgentTypes:   []string{"collaborator"},
+		ExcludeGroups:       []string{"Sandbox-*"},
+		IncludeRoles:        []string{"agent", "supervisor"},
+	}
+
+	agent := func(email, agentType string) *client.Agent {
+		return &client.Agent{Type: agentType, Contact: client.Contact{Email: email}}
+	}
+
+	assert.True(t, filters.agentIncluded(agent("jane@ACME.com", "support_agent")))
+	assert.False(t, filters.agentIncluded(agent("jane@partner.com", "support_agent")))
+	assert.False(t, filters.agentIncluded(agent("joe@acme.com", "collaborator")))
+
+	assert.True(t, filters.groupIncluded("Billing"))
+	assert.False(t, filters.groupIncluded("sandbox-billing"))
+
+	assert.True(t, filters.roleIncluded("Agent"))
+	assert.False(t, filters.roleIncluded("Administrator"))
+
+	var noFilters *Filters
+	assert.True(t, noFilters.agentIncluded(agent("jane@partner.com", "collaborator")))
+	assert.True(t, noFilters.groupIncluded("Sandbox-1"))
+	assert.True(t, noFilters.roleIncluded("Administrator"))
+}
+
+func TestAgentScopeIsListedOncePerSync(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	var listed atomic.Int64
+	agents := `[{"id":1,"type":"support_agent"},{"id":2,"type":"collaborator"}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		listed.Add(1)
+		_, _ = w.Write([]byte(agents))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	acme := &account{domain: "acme", client: c, filters: &Filters{ExcludeAgentTypes: []string{"collaborator"}}}
+
+	// Unknown creators and owners, reported as 0, are never looked up.
+	resource := &v2.Resource{Id: &v2.ResourceId{ResourceType: ticketTemplateResourceType.Id, Resource: "7"}}
+	grants, err := visibilityGrants(ctx, newAccountSet(acme), acme, resource, sharedItemViewerEntitlement, client.VisibilityPersonal, nil, []int64{0})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+	assert.Equal(t, int64(0), listed.Load())
+
+	inScope, err := acme.agentInScope(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, inScope)
+	inScope, err = acme.agentInScope(ctx, 2)
+	require.NoError(t, err)
+	assert.False(t, inScope)
+	assert.Equal(t, int64(1), listed.Load())
+
+	// The next sync sees the agents changed since.
+	agents = `[{"id":2,"type":"support_agent"}]`
+	acme.startSync()
+	inScope, err = acme.agentInScope(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, inScope)
+	assert.Equal(t, int64(2), listed.Load())
+}
@@ -2,10 +2,7 @@ package connector
 
 import (
 	"context"
-	"fmt"
 	"slices"
-	"strconv"
-	"sync"
 
 	"github.com/conductorone/baton-freshdesk/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -16,11 +13,14 @@ import (
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
 )
 
+// groupMemberEntitlement is held by the agents of a group. Resources routed to a group, such as
+// products and mailboxes, expand it.
+const groupMemberEntitlement = "member"
+
 type groupBuilder struct {
-	resourceType     *v2.ResourceType
-	client           *client.FreshdeskClient
-	agentsDetails    []client.Agent
-	agentDetailMutex sync.RWMutex
+	resourceType   *v2.ResourceType
+	accounts       *accountSet
+	grantsPageSize int
 }
 
 func (g *groupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
@@ -29,13 +29,22 @@ func (g *groupBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 
 func (g *groupBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var rv []*v2.Resource
-	bag, pageToken, err := getToken(pToken, roleResourceType)
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := g.accounts.fromAccountResourceID(parentResourceID)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	groups, nextPageToken, annotation, err := g.client.ListGroups(ctx, client.PageOptions{
-		Page:    pageToken,
+	bag, pageToken, err := getToken(pToken, groupResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	groups, nextPageToken, annotation, err := account.client.ListGroups(ctx, client.PageOptions{
+		Cursor:  pageToken,
 		PerPage: pToken.Size,
 	})
 	if err != nil {
@@ -47,9 +56,13 @@ func (g *groupBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId
 		return nil, "", nil, err
 	}
 
-	for _, group := range *groups {
+	for _, group := range groups {
+		if !account.filters.groupIncluded(group.Name) {
+			continue
+		}
+
 		groupCopy := group
-		userResource, err := parseIntoGroupResource(ctx, &groupCopy, parentResourceID)
+		userResource, err := parseIntoGroupResource(ctx, &groupCopy, g.accounts.resourceID(account, group.ID), parentResourceID)
 		if err != nil {
 			return nil, "", nil, err
 		}
@@ -66,7 +79,6 @@ func (g *groupBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId
 
 func (g *groupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
 	var rv []*v2.Entitlement
-	const permissionName = "member"
 
 	assigmentOptions := []entitlement.EntitlementOption{
 		entitlement.WithGrantableTo(userResourceType),
@@ -74,48 +86,81 @@ func (g *groupBuilder) Entitlements(_ context.Context, resource *v2.Resource, _
 		entitlement.WithDisplayName(resource.DisplayName),
 	}
 
-	rv = append(rv, entitlement.NewPermissionEntitlement(resource, permissionName, assigmentOptions...))
+	rv = append(rv, entitlement.NewPermissionEntitlement(resource, groupMemberEntitlement, assigmentOptions...))
 
 	return rv, "", nil, nil
 }
 
-func (g *groupBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+func (g *groupBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
 	var rv []*v2.Grant
-	err := g.GetAgentsDetails(ctx)
+
+	account, groupID, err := g.accounts.parseResourceID(resource.Id.Resource)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, agentDetail := range g.agentsDetails {
-		const permissionName = "member"
+	// Grants are paged over the agents list, so the bag holds the next agents page.
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
-		value, err := strconv.Atoi(resource.Id.Resource)
-		if err != nil {
-			return nil, "", nil, err
-		}
+	agents, nextPageToken, annotation, err := account.client.ListAgents(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: g.grantsPageSize,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	agentsDetails, err := account.agentDetails.GetAgentsDetails(ctx, agents)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
-		if slices.Contains(agentDetail.GroupIDs, int64(value)) {
-			userResource, _ := parseIntoUserResource(&agentDetail, nil)
+	for _, agentDetail := range agentsDetails {
+		if slices.Contains(agentDetail.GroupIDs, groupID) && account.filters.agentIncluded(&agentDetail) {
+			userID := &v2.ResourceId{
+				ResourceType: userResourceType.Id,
+				Resource:     g.accounts.resourceID(account, agentDetail.ID),
+			}
 
-			membershipGrant := grant.NewGrant(resource, permissionName, userResource.Id)
+			membershipGrant := grant.NewGrant(resource, groupMemberEntitlement, userID)
 			rv = append(rv, membershipGrant)
 		}
 	}
-	return rv, "", nil, nil
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
 }
 
-func newGroupBuilder(c *client.FreshdeskClient) *groupBuilder {
+func newGroupBuilder(accounts *accountSet, grantsPageSize int) *groupBuilder {
 	return &groupBuilder{
-		resourceType: groupResourceType,
-		client:       c,
+		resourceType:   groupResourceType,
+		accounts:       accounts,
+		grantsPageSize: grantsPageSize,
 	}
 }
 
 // This function parses a group from Freshdesk into a Group Resource.
-func parseIntoGroupResource(_ context.Context, group *client.Group, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+// A business_hour_id of 0 means the group follows the default business hours of the account.
+// The SDK has no annotation relating two resources, so the group is linked to its business hours by the
+// schedule grant of the business hour resource, which resolves the default; the profile field only
+// mirrors the group setting.
+func parseIntoGroupResource(_ context.Context, group *client.Group, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
 	profile := map[string]interface{}{
-		"group_id":   group.ID,
-		"group_name": group.Name,
+		"group_id":         group.ID,
+		"group_name":       group.Name,
+		"business_hour_id": group.BusinessHourID,
 	}
 
 	groupTraits := []rs.GroupTraitOption{
@@ -125,7 +170,7 @@ func parseIntoGroupResource(_ context.Context, group *client.Group, parentResour
 	ret, err := rs.NewGroupResource(
 		group.Name,
 		groupResourceType,
-		group.ID,
+		resourceID,
 		groupTraits,
 		rs.WithParentResourceID(parentResourceID),
 	)
@@ -136,74 +181,16 @@ func parseIntoGroupResource(_ context.Context, group *client.Group, parentResour
 	return ret, nil
 }
 
-func (g *groupBuilder) GetAgentsDetails(ctx context.Context) error {
-	g.agentDetailMutex.Lock()
-	defer g.agentDetailMutex.Unlock()
-
-	if g.agentsDetails != nil || len(g.agentsDetails) > 0 {
-		return nil
-	}
-
-	IDs, err := g.GetAllAgentsIDs(ctx)
-	if err != nil {
-		return err
-	}
-
-	if len(IDs) == 0 {
-		return fmt.Errorf("no agents found")
-	}
-
-	for _, id := range IDs {
-		agentDetail, _, err := g.client.GetAgentDetail(ctx, id)
-		if err != nil {
-			return err
-		}
-
-		g.agentsDetails = append(g.agentsDetails, *agentDetail)
-	}
-
-	return nil
-}
-
-func (g *groupBuilder) GetAllAgentsIDs(ctx context.Context) ([]string, error) {
-	var rv []string
-	paginationToken := pagination.Token{Size: 50, Token: ""}
-
-	for {
-		bag, pageToken, err := getToken(&paginationToken, userResourceType)
-		if err != nil {
-			return nil, err
-		}
-
-		agents, nextPageToken, _, err := g.client.ListAgents(ctx, client.PageOptions{
-			Page:    pageToken,
-			PerPage: paginationToken.Size,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		err = bag.Next(nextPageToken)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, agent := range agents {
-			agentID := strconv.FormatInt(agent.ID, 10)
-
-			rv = append(rv, agentID)
-		}
-
-		nextPageToken, err = bag.Marshal()
-		if err != nil {
-			return nil, err
-		}
-
-		if nextPageToken == "" {
-			break
-		}
-		paginationToken.Token = nextPageToken
-	}
-
-	return rv, nil
+// newGroupExpandedGrant grants an entitlement of resource to a group, expanded to the members of the group.
+func newGroupExpandedGrant(resource *v2.Resource, entitlementName string, groupID *v2.ResourceId) *v2.Grant {
+	groupResource := &v2.Resource{Id: groupID}
+
+	return grant.NewGrant(
+		resource,
+		entitlementName,
+		groupID,
+		grant.WithAnnotation(&v2.GrantExpandable{
+			EntitlementIds: []string{entitlement.NewEntitlementID(groupResource, groupMemberEntitlement)},
+		}),
+	)
 }
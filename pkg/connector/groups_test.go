@@ -0,0 +1,16 @@
+package connector
+
+import (
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupGrantsArePagedOverTheAgents(t *testing.T) {
+	accounts := newTestPagedAgentsAccountSet(t, nil, map[int64][]int64{2: {9}, 3: {4}, 4: {9}, 5: {4, 9}})
+	group := &v2.Resource{Id: &v2.ResourceId{ResourceType: groupResourceType.Id, Resource: "9"}}
+
+	// Two agents per page over five agents: three pages, the last one without a next token.
+	assert.Equal(t, [][]string{{"2"}, {"4"}, {"5"}}, pagedGrantPrincipals(t, newGroupBuilder(accounts, 2).Grants, group))
+}
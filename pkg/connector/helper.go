@@ -1,17 +1,18 @@
 package connector
 
 import (
-	"strconv"
+	"strings"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 )
 
-func getToken(pToken *pagination.Token, resourceType *v2.ResourceType) (*pagination.Bag, int, error) {
-	var pageToken int
-	_, bag, err := unmarshalSkipToken(pToken)
+// getToken unmarshals the pagination bag and returns the Freshdesk cursor (the next page URL) stored in it.
+func getToken(pToken *pagination.Token, resourceType *v2.ResourceType) (*pagination.Bag, string, error) {
+	bag := &pagination.Bag{}
+	err := bag.Unmarshal(pToken.Token)
 	if err != nil {
-		return bag, 0, err
+		return nil, "", err
 	}
 
 	if bag.Current() == nil {
@@ -20,32 +21,10 @@ func getToken(pToken *pagination.Token, resourceType *v2.ResourceType) (*paginat
 		})
 	}
 
-	if bag.Current().Token != "" {
-		pageToken, err = strconv.Atoi(bag.Current().Token)
-		if err != nil {
-			return bag, 0, err
-		}
-	}
-
-	return bag, pageToken, nil
+	return bag, bag.Current().Token, nil
 }
 
-func unmarshalSkipToken(token *pagination.Token) (int32, *pagination.Bag, error) {
-	b := &pagination.Bag{}
-	err := b.Unmarshal(token.Token)
-	if err != nil {
-		return 0, nil, err
-	}
-	current := b.Current()
-
-	ret := int32(0)
-	if current != nil && current.Token != "" {
-		currentToken, err := strconv.ParseInt(current.Token, 10, 32)
-		if err != nil {
-			return 0, nil, err
-		}
-		ret = int32(currentToken)
-	}
-
-	return ret, b, nil
+// entitlementSlug returns the last segment of an entitlement ID (resource_type:resource_id:slug).
+func entitlementSlug(entitlementID string) string {
+	return entitlementID[strings.LastIndex(entitlementID, ":")+1:]
 }
@@ -16,7 +16,7 @@ var (
 	ctx              = context.Background()
 	domain, _        = os.LookupEnv("FRESHDESK_DOMAIN")
 	apikey, _        = os.LookupEnv("FRESHDESK_TOKEN")
-	parentResourceID = &v2.ResourceId{}
+	parentResourceID = &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: domain}
 	pToken           = &pagination.Token{Size: 50, Token: ""}
 )
 
@@ -35,7 +35,7 @@ func TestUserBuilderList(t *testing.T) {
 		t.Errorf("ERROR: Failed to create client: %v", err)
 	}
 
-	u := newUserBuilder(c)
+	u := newUserBuilder(newTestAccountSet(c), AgentDefaults{})
 	res, _, _, err := u.List(ctx, parentResourceID, pToken)
 	assert.Nil(t, err)
 	assert.NotNil(t, res)
@@ -59,7 +59,7 @@ func TestRoleBuilderList(t *testing.T) {
 		t.Errorf("ERROR: Failed to create client: %v", err)
 	}
 
-	r := newRoleBuilder(c)
+	r := newRoleBuilder(newTestAccountSet(c), client.ItemsPerPage)
 
 	res, _, _, err := r.List(ctx, parentResourceID, pToken)
 	assert.Nil(t, err)
@@ -84,7 +84,7 @@ func TestGroupBuilderList(t *testing.T) {
 		t.Errorf("ERROR: Failed to create client: %v", err)
 	}
 
-	g := newGroupBuilder(c)
+	g := newGroupBuilder(newTestAccountSet(c), client.ItemsPerPage)
 	res, _, _, err := g.List(ctx, parentResourceID, pToken)
 	assert.Nil(t, err)
 	assert.NotNil(t, res)
@@ -92,3 +92,11 @@ func TestGroupBuilderList(t *testing.T) {
 	message := fmt.Sprintf("Amount of groups obtained: %d", len(res))
 	t.Log(message)
 }
+
+func newTestAccountSet(c *client.FreshdeskClient) *accountSet {
+	return newAccountSet(&account{
+		domain:       domain,
+		client:       c,
+		agentDetails: newAgentDetailFetcher(c, defaultAgentDetailsConcurrency),
+	})
+}
@@ -0,0 +1,202 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// productAgentEntitlement is held by the agents who work the tickets of a product, that is the members
+// of the groups its mailboxes route tickets to.
+const productAgentEntitlement = "agent"
+
+type productBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (p *productBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return p.resourceType
+}
+
+// List returns the products of the account, along with the URLs of their portals and their mailboxes.
+func (p *productBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := p.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, productResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	products, nextPageToken, annotation, err := account.client.ListProducts(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	portals, err := syncCached(account, "portals", func() ([]client.Portal, error) {
+		return account.client.ListAllPortals(ctx)
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	emailConfigs, err := accountEmailConfigs(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, product := range products {
+		productCopy := product
+		productResource, err := parseIntoProductResource(&productCopy, portals, emailConfigs, p.accounts.resourceID(account, product.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, productResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (p *productBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		productAgentEntitlement,
+		entitlement.WithGrantableTo(groupResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Product Agent", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Works the tickets of the %s product, as a member of a group its mailboxes route to", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants grants the product to every group one of its mailboxes routes tickets to, expanded to the group members.
+func (p *productBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, productID, err := p.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	emailConfigs, err := accountEmailConfigs(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var groupIDs []int64
+	for _, emailConfig := range emailConfigs {
+		if emailConfig.ProductID == productID && emailConfig.GroupID != 0 && !slices.Contains(groupIDs, emailConfig.GroupID) {
+			groupIDs = append(groupIDs, emailConfig.GroupID)
+		}
+	}
+
+	for _, groupID := range groupIDs {
+		inScope, err := account.groupInScope(ctx, groupID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		if !inScope {
+			continue
+		}
+
+		rv = append(rv, newGroupExpandedGrant(resource, productAgentEntitlement, &v2.ResourceId{
+			ResourceType: groupResourceType.Id,
+			Resource:     p.accounts.resourceID(account, groupID),
+		}))
+	}
+
+	return rv, "", nil, nil
+}
+
+// accountEmailConfigs returns the mailboxes of the account, read once per sync for all of its products.
+func accountEmailConfigs(ctx context.Context, account *account) ([]client.EmailConfig, error) {
+	return syncCached(account, "email_configs", func() ([]client.EmailConfig, error) {
+		return account.client.ListAllEmailConfigs(ctx)
+	})
+}
+
+func newProductBuilder(accounts *accountSet) *productBuilder {
+	return &productBuilder{
+		resourceType: productResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoProductResource - This function parses a Freshdesk product into an App Resource.
+// The profile lists the URLs of the product portals and the addresses of its mailboxes, primary ones first.
+func parseIntoProductResource(
+	product *client.Product,
+	portals []client.Portal,
+	emailConfigs []client.EmailConfig,
+	resourceID string,
+	parentResourceID *v2.ResourceId,
+) (*v2.Resource, error) {
+	var portalURLs, primaryEmails, emails []string
+	for _, portal := range portals {
+		if portal.ProductID == product.ID && portal.PortalURL != "" {
+			portalURLs = append(portalURLs, portal.PortalURL)
+		}
+	}
+
+	for _, emailConfig := range emailConfigs {
+		if emailConfig.ProductID != product.ID {
+			continue
+		}
+
+		if emailConfig.PrimaryRole {
+			primaryEmails = append(primaryEmails, emailConfig.ToEmail)
+		} else {
+			emails = append(emails, emailConfig.ToEmail)
+		}
+	}
+
+	profile := map[string]interface{}{
+		"product_id":     product.ID,
+		"product_name":   product.Name,
+		"primary_email":  product.PrimaryEmail,
+		"portal_urls":    strings.Join(portalURLs, ","),
+		"default_emails": strings.Join(primaryEmails, ","),
+		"email_configs":  strings.Join(append(primaryEmails, emails...), ","),
+	}
+
+	return rs.NewAppResource(
+		product.Name,
+		productResourceType,
+		resourceID,
+		[]rs.AppTraitOption{rs.WithAppProfile(profile)},
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(product.Description),
+	)
+}
@@ -0,0 +1,81 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductBuilder(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	var portalLists, emailConfigLists atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/products":
+			_, _ = w.Write([]byte(`[{"id":1,"name":"Widgets"},{"id":2,"name":"Gadgets"}]`))
+		case "/api/v2/portals":
+			portalLists.Add(1)
+			_, _ = w.Write([]byte(`[{"id":5,"portal_url":"widgets.acme.com","product_id":1}]`))
+		case "/api/v2/email_configs":
+			emailConfigLists.Add(1)
+			_, _ = w.Write([]byte(`[` +
+				`{"id":7,"to_email":"help@widgets.acme.com","product_id":1,"group_id":3},` +
+				`{"id":8,"to_email":"support@widgets.acme.com","product_id":1,"group_id":4,"primary_role":true},` +
+				`{"id":9,"to_email":"billing@widgets.acme.com","product_id":1,"group_id":3}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	acme := &account{domain: "acme", client: c}
+	products := newProductBuilder(newAccountSet(acme))
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "acme"}
+
+	resources, _, _, err := products.List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	appTrait, err := rs.GetAppTrait(resources[0])
+	require.NoError(t, err)
+	portalURLs, _ := rs.GetProfileStringValue(appTrait.Profile, "portal_urls")
+	assert.Equal(t, "widgets.acme.com", portalURLs)
+	emailConfigs, _ := rs.GetProfileStringValue(appTrait.Profile, "email_configs")
+	assert.Equal(t, "support@widgets.acme.com,help@widgets.acme.com,billing@widgets.acme.com", emailConfigs)
+
+	grants, _, _, err := products.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, &v2.ResourceId{ResourceType: groupResourceType.Id, Resource: "3"}, grants[0].Principal.Id)
+	assert.Equal(t, &v2.ResourceId{ResourceType: groupResourceType.Id, Resource: "4"}, grants[1].Principal.Id)
+
+	grants, _, _, err = products.Grants(ctx, resources[1], &pagination.Token{})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+
+	// Portals and mailboxes are read once per sync, whatever the number of pages and products.
+	_, _, _, err = products.List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), portalLists.Load())
+	assert.Equal(t, int64(1), emailConfigLists.Load())
+
+	acme.startSync()
+	_, _, _, err = products.List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), portalLists.Load())
+	assert.Equal(t, int64(2), emailConfigLists.Load())
+}
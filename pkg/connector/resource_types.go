@@ -6,6 +6,13 @@ import (
 
 // The user resource type is for all user objects from the database.
 var (
+	accountResourceType = &v2.ResourceType{
+		Id:          "account",
+		DisplayName: "Account",
+		Description: "The Freshdesk account (helpdesk), root of the agents, roles, groups, companies and knowledge base, along with its owner, administrators and licenses",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
+	}
+
 	userResourceType = &v2.ResourceType{
 		Id:          "user",
 		DisplayName: "User",
@@ -13,6 +20,13 @@ var (
 		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_USER},
 	}
 
+	contactResourceType = &v2.ResourceType{
+		Id:          "contact",
+		DisplayName: "Contact",
+		Description: "The Contacts are the customers of the helpdesk, who log in to the support portal once they accept their invite",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_USER},
+	}
+
 	roleResourceType = &v2.ResourceType{
 		Id:          "role",
 		DisplayName: "Role",
@@ -26,4 +40,79 @@ var (
 		Description: "The Agents can be organized into different groups. It's useful for the organization of users.",
 		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
 	}
+
+	companyResourceType = &v2.ResourceType{
+		Id:          "company",
+		DisplayName: "Company",
+		Description: "The Companies group the customer contacts of an organization, and can be given access to knowledge-base folders",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+	}
+
+	contactSegmentResourceType = &v2.ResourceType{
+		Id:          "contact_segment",
+		DisplayName: "Contact Segment",
+		Description: "The Contact Segments are saved contact filters, and can be given access to knowledge-base folders",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+	}
+
+	productResourceType = &v2.ResourceType{
+		Id:          "product",
+		DisplayName: "Product",
+		Description: "The Products (brands) of a multi-product helpdesk, with their portals and support mailboxes",
+		Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
+	}
+
+	emailConfigResourceType = &v2.ResourceType{
+		Id:          "email_config",
+		DisplayName: "Email Config",
+		Description: "The support mailboxes of the helpdesk. The agents of the group a mailbox routes tickets to can read its emails",
+	}
+
+	cannedResponseFolderResourceType = &v2.ResourceType{
+		Id:          "canned_response_folder",
+		DisplayName: "Canned Response Folder",
+		Description: "The folders of canned responses, which agents share with each other or with groups and may hold internal procedures",
+	}
+
+	ticketTemplateResourceType = &v2.ResourceType{
+		Id:          "ticket_template",
+		DisplayName: "Ticket Template",
+		Description: "The ticket templates agents share with each other or with groups",
+	}
+
+	scenarioAutomationResourceType = &v2.ResourceType{
+		Id:          "scenario_automation",
+		DisplayName: "Scenario Automation",
+		Description: "The scenario automations run bulk ticket actions in one click, for every agent or for selected agents or groups",
+	}
+
+	automationRuleResourceType = &v2.ResourceType{
+		Id:          "automation_rule",
+		DisplayName: "Automation Rule",
+		Description: "The ticket creation, ticket update and time trigger automation rules, which only administrators can change",
+	}
+
+	businessHourResourceType = &v2.ResourceType{
+		Id:          "business_hour",
+		DisplayName: "Business Hours",
+		Description: "The business hours calendars the SLA timers of a group's tickets follow",
+	}
+
+	slaPolicyResourceType = &v2.ResourceType{
+		Id:          "sla_policy",
+		DisplayName: "SLA Policy",
+		Description: "The SLA policies setting the response and resolution targets of the tickets of some groups",
+	}
+
+	solutionCategoryResourceType = &v2.ResourceType{
+		Id:          "solution_category",
+		DisplayName: "Solution Category",
+		Description: "The knowledge-base (Solutions) Categories hold the folders of articles shown in the support portals",
+	}
+
+	solutionFolderResourceType = &v2.ResourceType{
+		Id:          "solution_folder",
+		DisplayName: "Solution Folder",
+		Description: "The knowledge-base (Solutions) Folders hold articles visible to everyone, to agents only, or to selected companies or contact segments",
+	}
 )
@@ -6,7 +6,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/conductorone/baton-freshdesk/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -19,11 +19,26 @@ import (
 	"go.uber.org/zap"
 )
 
+// roleAssignedEntitlement is held by the agents a role is assigned to.
+const roleAssignedEntitlement = "assigned"
+
+// Risk classifications of a role. Privileged roles can change the helpdesk configuration and
+// should require extra approval.
+const (
+	roleRiskPrivileged = "privileged"
+	roleRiskStandard   = "standard"
+)
+
+// Names of the built-in Freshdesk roles an agent-type role can be told apart from an admin-type one by.
+const (
+	supervisorRoleName = "Supervisor"
+	agentRoleName      = "Agent"
+)
+
 type roleBuilder struct {
-	resourceType      *v2.ResourceType
-	agentsDetails     []client.Agent
-	agentDetailsMutex sync.RWMutex
-	client            *client.FreshdeskClient
+	resourceType   *v2.ResourceType
+	accounts       *accountSet
+	grantsPageSize int
 }
 
 func (r *roleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
@@ -32,13 +47,22 @@ func (r *roleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 
 func (r *roleBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := r.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
 	bag, pageToken, err := getToken(pToken, roleResourceType)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	roles, nextPageToken, annotation, err := r.client.ListRoles(ctx, client.PageOptions{
-		Page:    pageToken,
+	roles, nextPageToken, annotation, err := account.client.ListRoles(ctx, client.PageOptions{
+		Cursor:  pageToken,
 		PerPage: pToken.Size,
 	})
 	if err != nil {
@@ -50,9 +74,13 @@ func (r *roleBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 		return nil, "", nil, err
 	}
 
-	for _, role := range *roles {
+	for _, role := range roles {
+		if !account.filters.roleIncluded(role.Name) {
+			continue
+		}
+
 		roleCopy := role
-		roleResource, err := parseIntoRoleResource(ctx, &roleCopy, parentResourceID)
+		roleResource, err := parseIntoRoleResource(ctx, &roleCopy, r.accounts.resourceID(account, role.ID), parentResourceID)
 		if err != nil {
 			return nil, "", nil, err
 		}
@@ -69,42 +97,87 @@ func (r *roleBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 
 func (r *roleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
 	var rv []*v2.Entitlement
-	permissionName := "assigned"
+
+	risk, isDefault := roleRiskFromResource(resource)
+	description := resource.Description
+	if isDefault {
+		description = strings.TrimSpace("Default role, it can be granted but not revoked. " + description)
+	}
+	if risk == roleRiskPrivileged {
+		description = "Privileged role. " + description
+	}
 
 	assigmentOptions := []entitlement.EntitlementOption{
 		entitlement.WithGrantableTo(userResourceType),
-		entitlement.WithDescription(resource.Description),
+		entitlement.WithDescription(strings.TrimSpace(description)),
 		entitlement.WithDisplayName(resource.DisplayName),
 	}
 
-	rv = append(rv, entitlement.NewPermissionEntitlement(resource, permissionName, assigmentOptions...))
+	rv = append(rv, entitlement.NewPermissionEntitlement(resource, roleAssignedEntitlement, assigmentOptions...))
 
 	return rv, "", nil, nil
 }
 
-func (r *roleBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+func (r *roleBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
 	var rv []*v2.Grant
-	err := r.GetAgentsDetails(ctx)
+
+	account, roleID, err := r.accounts.parseResourceID(resource.Id.Resource)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	for _, agentDetail := range r.agentsDetails {
-		const permissionName = "assigned"
+	// Grants are paged over the agents list, so the bag holds the next agents page.
+	bag, pageToken, err := getToken(pToken, userResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
-		value, err := strconv.Atoi(resource.Id.Resource)
+	agents, nextPageToken, annotation, err := account.client.ListAgents(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: r.grantsPageSize,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
 
-		if err != nil {
-			return nil, "", nil, err
-		}
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
-		if slices.Contains(agentDetail.RoleIDs, int64(value)) {
-			userResource, _ := parseIntoUserResource(&agentDetail, nil)
-			membershipGrant := grant.NewGrant(resource, permissionName, userResource.Id)
+	agentsDetails, err := account.agentDetails.GetAgentsDetails(ctx, agents)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	risk, isDefault := roleRiskFromResource(resource)
+	grantOptions := []grant.GrantOption{
+		grant.WithGrantMetadata(map[string]interface{}{
+			"risk":      risk,
+			"revocable": !isDefault,
+		}),
+	}
+	if isDefault {
+		grantOptions = append(grantOptions, grant.WithAnnotation(&v2.GrantImmutable{}))
+	}
+
+	for _, agentDetail := range agentsDetails {
+		if slices.Contains(agentDetail.RoleIDs, roleID) && account.filters.agentIncluded(&agentDetail) {
+			userID := &v2.ResourceId{
+				ResourceType: userResourceType.Id,
+				Resource:     r.accounts.resourceID(account, agentDetail.ID),
+			}
+			membershipGrant := grant.NewGrant(resource, roleAssignedEntitlement, userID, grantOptions...)
 			rv = append(rv, membershipGrant)
 		}
 	}
-	return rv, "", nil, nil
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
 }
 
 func (r *roleBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
@@ -116,20 +189,19 @@ func (r *roleBuilder) Grant(ctx context.Context, principal *v2.Resource, entitle
 		return nil, fmt.Errorf("freshdesk-connector: only users can be granted with role membership")
 	}
 
-	userID := principal.Id.Resource
-	roleID, err := strconv.ParseInt(entitlement.Resource.Id.Resource, 10, 64)
+	account, agentID, roleID, err := r.agentAndRole(principal.Id.Resource, entitlement.Resource.Id.Resource)
 	if err != nil {
 		return nil, err
 	}
 
-	agent, _, err := r.client.GetAgentDetail(ctx, userID)
+	agent, _, err := account.client.GetAgentDetail(ctx, strconv.FormatInt(agentID, 10))
 	if err != nil {
 		return nil, err
 	}
 
 	agent.RoleIDs = append(agent.RoleIDs, roleID)
 
-	anno, err := r.client.UpdateAgent(ctx, agent)
+	anno, err := account.client.UpdateAgent(ctx, agent)
 	if err != nil {
 		return nil, err
 	}
@@ -138,13 +210,28 @@ func (r *roleBuilder) Grant(ctx context.Context, principal *v2.Resource, entitle
 }
 
 func (r *roleBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
-	userID := grant.Principal.Id.Resource
-	roleID, err := ExtractRoleIDFromEntitlement(grant.Entitlement.Id)
+	roleResourceID, err := ExtractRoleIDFromEntitlement(grant.Entitlement.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, agentID, roleID, err := r.agentAndRole(grant.Principal.Id.Resource, roleResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := account.client.ListAllRoles(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	agent, _, err := r.client.GetAgentDetail(ctx, userID)
+	for _, role := range roles {
+		if role.ID == roleID && role.Default {
+			return nil, fmt.Errorf("baton-freshdesk: %s is a default Freshdesk role and can't be revoked", role.Name)
+		}
+	}
+
+	agent, _, err := account.client.GetAgentDetail(ctx, strconv.FormatInt(agentID, 10))
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +244,7 @@ func (r *roleBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.
 	}
 	agent.RoleIDs = assignedRoles
 
-	anno, err := r.client.UpdateAgent(ctx, agent)
+	anno, err := account.client.UpdateAgent(ctx, agent)
 	if err != nil {
 		return nil, err
 	}
@@ -165,98 +252,71 @@ func (r *roleBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.
 	return anno, nil
 }
 
-func (r *roleBuilder) GetAllAgentsIDs(ctx context.Context) ([]string, error) {
-	var rv []string
-	paginationToken := pagination.Token{Size: 50, Token: ""}
-
-	for {
-		bag, pageToken, err := getToken(&paginationToken, userResourceType)
-		if err != nil {
-			return nil, err
-		}
-
-		agents, nextPageToken, _, err := r.client.ListAgents(ctx, client.PageOptions{
-			Page:    pageToken,
-			PerPage: paginationToken.Size,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		err = bag.Next(nextPageToken)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, agent := range agents {
-			agentID := strconv.FormatInt(agent.ID, 10)
-
-			rv = append(rv, agentID)
-		}
-
-		nextPageToken, err = bag.Marshal()
-		if err != nil {
-			return nil, err
-		}
-
-		if nextPageToken == "" {
-			break
-		}
-		paginationToken.Token = nextPageToken
-	}
-
-	return rv, nil
-}
-
-func (r *roleBuilder) GetAgentsDetails(ctx context.Context) error {
-	r.agentDetailsMutex.Lock()
-	defer r.agentDetailsMutex.Unlock()
-
-	if r.agentsDetails != nil || len(r.agentsDetails) > 0 {
-		return nil
-	}
-
-	IDs, err := r.GetAllAgentsIDs(ctx)
+// agentAndRole resolves the user and role resource IDs of a role assignment, which must belong to the same account.
+func (r *roleBuilder) agentAndRole(userResourceID, roleResourceID string) (*account, int64, int64, error) {
+	account, agentID, err := r.accounts.parseResourceID(userResourceID)
 	if err != nil {
-		return err
+		return nil, 0, 0, err
 	}
 
-	if len(IDs) == 0 {
-		return fmt.Errorf("no agents found")
+	roleAccount, roleID, err := r.accounts.parseResourceID(roleResourceID)
+	if err != nil {
+		return nil, 0, 0, err
 	}
 
-	for _, id := range IDs {
-		agentDetail, _, err := r.client.GetAgentDetail(ctx, id)
-		if err != nil {
-			return err
-		}
-
-		r.agentsDetails = append(r.agentsDetails, *agentDetail)
+	if roleAccount != account {
+		return nil, 0, 0, fmt.Errorf("baton-freshdesk: user %s and role %s belong to different accounts", userResourceID, roleResourceID)
 	}
 
-	return nil
+	return account, agentID, roleID, nil
 }
 
-func newRoleBuilder(c *client.FreshdeskClient) *roleBuilder {
+func newRoleBuilder(accounts *accountSet, grantsPageSize int) *roleBuilder {
 	return &roleBuilder{
-		resourceType: roleResourceType,
-		client:       c,
+		resourceType:   roleResourceType,
+		accounts:       accounts,
+		grantsPageSize: grantsPageSize,
 	}
 }
 
 // This function parses a role from Freshdesk into a Role Resource.
-func parseIntoRoleResource(_ context.Context, role *client.Role, _ *v2.ResourceId) (*v2.Resource, error) {
+// The profile tells whether the role is built in, the agent type it applies to, whether it is an admin-type
+// or agent-type role and how risky granting it is.
+func parseIntoRoleResource(_ context.Context, role *client.Role, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	roleType := roleTypeName(role)
+	risk := roleRiskStandard
+	if roleType == "admin" {
+		risk = roleRiskPrivileged
+	}
+
 	profile := map[string]interface{}{
 		"id":          role.ID,
 		"name":        role.Name,
 		"description": role.Description,
+		"default":     role.Default,
+		"agent_type":  roleAgentTypeName(role.AgentType),
+		"role_type":   roleType,
+		"risk":        risk,
+	}
+
+	if !role.CreatedAt.IsZero() {
+		profile["created_at"] = role.CreatedAt.Format(time.RFC3339)
+	}
+	if !role.UpdatedAt.IsZero() {
+		profile["updated_at"] = role.UpdatedAt.Format(time.RFC3339)
 	}
 
 	roleTraits := []rs.RoleTraitOption{
 		rs.WithRoleProfile(profile),
 	}
 
-	ret, err := rs.NewRoleResource(role.Name, roleResourceType, role.ID, roleTraits)
+	ret, err := rs.NewRoleResource(
+		role.Name,
+		roleResourceType,
+		resourceID,
+		roleTraits,
+		rs.WithParentResourceID(parentResourceID),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -264,16 +324,59 @@ func parseIntoRoleResource(_ context.Context, role *client.Role, _ *v2.ResourceI
 	return ret, nil
 }
 
-func ExtractRoleIDFromEntitlement(entitlementID string) (int64, error) {
-	segments := strings.Split(entitlementID, ":")
-	if len(segments) != 3 {
-		return 0, fmt.Errorf("baton-freshdesk: invalid entitlement ID %s", entitlementID)
+// roleTypeName tells admin-type roles, which manage the helpdesk configuration, from agent-type roles, which
+// work tickets. Freshdesk doesn't expose the privileges of a role, so only built-in roles are classified and
+// custom roles are reported as such.
+func roleTypeName(role *client.Role) string {
+	if !role.Default {
+		return "custom"
 	}
 
-	roleID, err := strconv.ParseInt(segments[1], 10, 64)
+	switch role.Name {
+	case accountAdminRoleName, adminRoleName:
+		return "admin"
+	case supervisorRoleName, agentRoleName:
+		return "agent"
+	default:
+		return "custom"
+	}
+}
+
+// roleAgentTypeName translates the agent type a role applies to, using the names agents report their type with.
+func roleAgentTypeName(agentType int64) string {
+	switch agentType {
+	case client.RoleAgentTypeSupport:
+		return "support_agent"
+	case client.RoleAgentTypeFieldService:
+		return "field_agent"
+	case client.RoleAgentTypeCollaborator:
+		return "collaborator"
+	default:
+		return "unknown"
+	}
+}
+
+// roleRiskFromResource reads the risk classification and the default flag from the profile of a role resource.
+func roleRiskFromResource(resource *v2.Resource) (string, bool) {
+	roleTrait, err := rs.GetRoleTrait(resource)
 	if err != nil {
-		return 0, err
+		return roleRiskStandard, false
+	}
+
+	risk, ok := rs.GetProfileStringValue(roleTrait.Profile, "risk")
+	if !ok {
+		risk = roleRiskStandard
+	}
+
+	return risk, roleTrait.Profile.GetFields()["default"].GetBoolValue()
+}
+
+// ExtractRoleIDFromEntitlement returns the role resource ID from a role:[id]:assigned entitlement ID.
+func ExtractRoleIDFromEntitlement(entitlementID string) (string, error) {
+	segments := strings.Split(entitlementID, ":")
+	if len(segments) != 3 || segments[1] == "" {
+		return "", fmt.Errorf("baton-freshdesk: invalid entitlement ID %s", entitlementID)
 	}
 
-	return roleID, nil
+	return segments[1], nil
 }
@@ -0,0 +1,145 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPagedAgentsAccountSet serves agents 1 to 5 two per page, each holding the roles and groups given.
+func newTestPagedAgentsAccountSet(t *testing.T, roleIDs, groupIDs map[int64][]int64) *accountSet {
+	t.Helper()
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	const agentCount, perPage = 5, 2
+	agent := func(id int64) string {
+		return fmt.Sprintf(`{"id":%d,"role_ids":%s,"group_ids":%s}`, id, jsonIDs(roleIDs[id]), jsonIDs(groupIDs[id]))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/api/v2/agents" {
+			if r.URL.Query().Get("per_page") != strconv.Itoa(perPage) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			page = max(page, 1)
+			var agents []string
+			for id := int64((page-1)*perPage + 1); id <= int64(page*perPage) && id <= agentCount; id++ {
+				agents = append(agents, agent(id))
+			}
+			if page*perPage < agentCount {
+				w.Header().Set("Link", fmt.Sprintf(`</api/v2/agents?per_page=%d&page=%d>; rel="next"`, perPage, page+1))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(agents, ",") + "]"))
+			return
+		}
+
+		id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/v2/agents/"), 10, 64)
+		if err != nil || id < 1 || id > agentCount {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(agent(id)))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	// The details are fetched one at a time: the no-op cache the SDK uses once disabled isn't safe for
+	// concurrent use.
+	return newAccountSet(&account{
+		domain:       "acme",
+		client:       c,
+		agentDetails: newAgentDetailFetcher(c, 1),
+	})
+}
+
+func jsonIDs(ids []int64) string {
+	var rv []string
+	for _, id := range ids {
+		rv = append(rv, strconv.FormatInt(id, 10))
+	}
+
+	return "[" + strings.Join(rv, ",") + "]"
+}
+
+// pagedGrantPrincipals reads every page of grants of a resource, checking that only the last page has no
+// next token, and returns the principals of the grants of each page.
+func pagedGrantPrincipals(
+	t *testing.T,
+	grants func(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error),
+	resource *v2.Resource,
+) [][]string {
+	t.Helper()
+
+	var rv [][]string
+	token := &pagination.Token{}
+	for {
+		page, nextToken, _, err := grants(ctx, resource, token)
+		require.NoError(t, err)
+
+		var principals []string
+		for _, g := range page {
+			assert.Equal(t, userResourceType.Id, g.Principal.Id.ResourceType)
+			principals = append(principals, g.Principal.Id.Resource)
+		}
+		rv = append(rv, principals)
+
+		if nextToken == "" {
+			return rv
+		}
+		require.Less(t, len(rv), 10, "grants never ran out of pages")
+		token = &pagination.Token{Token: nextToken}
+	}
+}
+
+func TestRoleRiskClassification(t *testing.T) {
+	testCases := []struct {
+		role      client.Role
+		roleType  string
+		risk      string
+		isDefault bool
+	}{
+		{client.Role{ID: 1, Name: accountAdminRoleName, Default: true}, "admin", roleRiskPrivileged, true},
+		{client.Role{ID: 2, Name: adminRoleName, Default: true}, "admin", roleRiskPrivileged, true},
+		{client.Role{ID: 3, Name: agentRoleName, Default: true}, "agent", roleRiskStandard, true},
+		{client.Role{ID: 4, Name: adminRoleName}, "custom", roleRiskStandard, false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.roleType, roleTypeName(&tc.role), tc.role.Name)
+
+		resource, err := parseIntoRoleResource(context.Background(), &tc.role, "1", nil)
+		require.NoError(t, err)
+
+		risk, isDefault := roleRiskFromResource(resource)
+		assert.Equal(t, tc.risk, risk, tc.role.Name)
+		assert.Equal(t, tc.isDefault, isDefault, tc.role.Name)
+	}
+}
+
+func TestRoleGrantsArePagedOverTheAgents(t *testing.T) {
+	accounts := newTestPagedAgentsAccountSet(t, map[int64][]int64{1: {7}, 2: {8}, 3: {7, 8}, 5: {7}}, nil)
+
+	role, err := parseIntoRoleResource(ctx, &client.Role{ID: 7, Name: "Supervisor"}, "7", nil)
+	require.NoError(t, err)
+
+	// Two agents per page over five agents: three pages, the last one without a next token.
+	assert.Equal(t, [][]string{{"1"}, {"3"}, {"5"}}, pagedGrantPrincipals(t, newRoleBuilder(accounts, 2).Grants, role))
+}
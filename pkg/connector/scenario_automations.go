@@ -0,0 +1,179 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	scenarioRunnerEntitlement    = "runner"
+	automationCreatorEntitlement = "creator"
+)
+
+type scenarioAutomationBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (s *scenarioAutomationBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return s.resourceType
+}
+
+// List returns the scenario automations of the account.
+func (s *scenarioAutomationBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := s.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, scenarioAutomationResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	scenarios, nextPageToken, annotation, err := account.client.ListScenarioAutomations(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, scenario := range scenarios {
+		scenarioCopy := scenario
+		scenarioResource, err := parseIntoScenarioAutomationResource(&scenarioCopy, s.accounts.resourceID(account, scenario.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, scenarioResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (s *scenarioAutomationBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		scenarioRunnerEntitlement,
+		entitlement.WithGrantableTo(userResourceType, groupResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Scenario Runner", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Can run the %s scenario on tickets", resource.DisplayName)),
+	))
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		automationCreatorEntitlement,
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Scenario Creator", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Created the %s scenario", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants returns the agents and groups a restricted scenario is visible to, along with its creator.
+// Scenarios visible to every agent only have a creator grant.
+func (s *scenarioAutomationBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	account, scenarioID, err := s.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	scenarios, err := s.accountScenarios(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	scenario, ok := scenarios[scenarioID]
+	if !ok {
+		return nil, "", nil, nil
+	}
+
+	rv, err := visibilityGrants(ctx, s.accounts, account, resource, scenarioRunnerEntitlement, scenario.Visibility, scenario.GroupIDs, scenario.AgentIDs)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	creatorInScope := false
+	if scenario.CreatedBy != 0 {
+		creatorInScope, err = account.agentInScope(ctx, scenario.CreatedBy)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	if creatorInScope {
+		rv = append(rv, grant.NewGrant(resource, automationCreatorEntitlement, &v2.ResourceId{
+			ResourceType: userResourceType.Id,
+			Resource:     s.accounts.resourceID(account, scenario.CreatedBy),
+		}))
+	}
+
+	return rv, "", nil, nil
+}
+
+// accountScenarios returns the scenarios of the account by ID, read once per sync.
+func (s *scenarioAutomationBuilder) accountScenarios(ctx context.Context, account *account) (map[int64]client.ScenarioAutomation, error) {
+	return syncCached(account, "scenario_automations", func() (map[int64]client.ScenarioAutomation, error) {
+		list, err := account.client.ListAllScenarioAutomations(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		scenarios := make(map[int64]client.ScenarioAutomation, len(list))
+		for _, scenario := range list {
+			scenarios[scenario.ID] = scenario
+		}
+
+		return scenarios, nil
+	})
+}
+
+func newScenarioAutomationBuilder(accounts *accountSet) *scenarioAutomationBuilder {
+	return &scenarioAutomationBuilder{
+		resourceType: scenarioAutomationResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoScenarioAutomationResource - This function parses a scenario automation into a Resource.
+// Who can run the scenario leads its description.
+func parseIntoScenarioAutomationResource(scenario *client.ScenarioAutomation, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := fmt.Sprintf("Visible to %s", visibilityName(scenario.Visibility))
+	if scenario.Description != "" {
+		description += ". " + scenario.Description
+	}
+
+	return rs.NewResource(
+		scenario.Name,
+		scenarioAutomationResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
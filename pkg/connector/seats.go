@@ -0,0 +1,185 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+)
+
+// Seat categories, named after the total_agents counters of the account endpoint.
+const (
+	seatFullTime     = "full_time"
+	seatOccasional   = "occasional"
+	seatFieldService = "field_service"
+	seatCollaborator = "collaborators"
+)
+
+// seatUsageTTL is how long seat checks reuse the seat usage last counted, so that granting licenses to
+// many agents in a row does not list every agent of the account for each of them.
+const seatUsageTTL = time.Minute
+
+// SeatCategories lists the seat categories a limit can be set for.
+var SeatCategories = []string{seatFullTime, seatOccasional, seatFieldService, seatCollaborator}
+
+// SeatLimit is the number of seats of one category purchased for an account.
+// The account endpoint only reports the seats in use, so limits come from the configuration.
+// An empty Domain applies the limit to every account without a limit of its own.
+type SeatLimit struct {
+	Domain   string
+	Category string
+	Limit    int64
+}
+
+// seatUsage counts the agents of an account per seat category and per agent type and license type.
+type seatUsage struct {
+	byCategory map[string]int64
+	byLicense  map[string]int64
+}
+
+// seatCategory returns the seat an agent consumes. Field technicians and collaborators have their
+// own seats; support agents use a full-time license or, when occasional, day passes.
+func seatCategory(agent *client.Agent) string {
+	switch agent.Type {
+	case "field_agent":
+		return seatFieldService
+	case "collaborator":
+		return seatCollaborator
+	}
+
+	return licenseType(agent)
+}
+
+func countSeats(agents []client.Agent) *seatUsage {
+	usage := &seatUsage{
+		byCategory: make(map[string]int64),
+		byLicense:  make(map[string]int64),
+	}
+
+	for i := range agents {
+		agent := &agents[i]
+		usage.byCategory[seatCategory(agent)]++
+		usage.byLicense[agent.Type+"_"+licenseType(agent)]++
+	}
+
+	return usage
+}
+
+// profile returns the seat usage as account profile fields: seats_used_<category> for every category,
+// seats_limit_<category> and seats_available_<category> for the limited ones, and
+// agents_<agent type>_<license type> for every combination in use.
+func (s *seatUsage) profile(limits map[string]int64) map[string]interface{} {
+	profile := make(map[string]interface{})
+	for _, category := range SeatCategories {
+		used := s.byCategory[category]
+		profile["seats_used_"+category] = used
+
+		limit, ok := limits[category]
+		if !ok {
+			continue
+		}
+		profile["seats_limit_"+category] = limit
+		profile["seats_available_"+category] = max(limit-used, 0)
+	}
+
+	licenses := make([]string, 0, len(s.byLicense))
+	for license := range s.byLicense {
+		licenses = append(licenses, license)
+	}
+	sort.Strings(licenses)
+	for _, license := range licenses {
+		profile["agents_"+license] = s.byLicense[license]
+	}
+
+	return profile
+}
+
+// seatUsage lists every agent of the account and counts the seats they use.
+// The count is kept for the seat checks that follow.
+func (a *account) seatUsage(ctx context.Context) (*seatUsage, error) {
+	agents, err := a.client.ListAllAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := countSeats(agents)
+
+	a.seatsMutex.Lock()
+	a.seats = usage.clone()
+	a.seatsCountedAt = time.Now()
+	a.seatsMutex.Unlock()
+
+	return usage, nil
+}
+
+func (s *seatUsage) clone() *seatUsage {
+	return &seatUsage{
+		byCategory: maps.Clone(s.byCategory),
+		byLicense:  maps.Clone(s.byLicense),
+	}
+}
+
+// checkSeatAvailable refuses to assign one more seat of category when the account has used all of them.
+// Categories without a configured limit are never refused.
+func (a *account) checkSeatAvailable(ctx context.Context, category string) error {
+	return a.checkSeatsAvailable(ctx, map[string]int64{category: 1})
+}
+
+// checkSeatsAvailable refuses to assign the requested number of seats per category when it would
+// exceed the limit of any of them. Seats it accepts are counted as used until the agents are listed
+// again, even when the assignment fails afterwards, which can only make the check stricter.
+func (a *account) checkSeatsAvailable(ctx context.Context, requested map[string]int64) error {
+	limited := false
+	for category := range requested {
+		if _, ok := a.seatLimits[category]; ok {
+			limited = true
+		}
+	}
+	if !limited {
+		return nil
+	}
+
+	a.seatsMutex.Lock()
+	usage := a.seats
+	if usage != nil && time.Since(a.seatsCountedAt) > seatUsageTTL {
+		usage = nil
+	}
+	a.seatsMutex.Unlock()
+
+	if usage == nil {
+		_, err := a.seatUsage(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Checking and reserving under the lock keeps concurrent grants from sharing the last seat.
+	a.seatsMutex.Lock()
+	defer a.seatsMutex.Unlock()
+	usage = a.seats
+
+	for _, category := range SeatCategories {
+		limit, ok := a.seatLimits[category]
+		if !ok || requested[category] == 0 {
+			continue
+		}
+
+		used := usage.byCategory[category]
+		if used+requested[category] > limit {
+			if requested[category] == 1 {
+				return fmt.Errorf("baton-freshdesk: no %s seat available in account %s: %d of %d in use", category, a.domain, used, limit)
+			}
+			return fmt.Errorf("baton-freshdesk: not enough %s seats available in account %s for %d agents: %d of %d in use",
+				category, a.domain, requested[category], used, limit)
+		}
+	}
+
+	for category, count := range requested {
+		usage.byCategory[category] += count
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeatUsageProfile(t *testing.T) {
+	usage := countSeats([]client.Agent{
+		{Type: "support_agent"},
+		{Type: "support_agent"},
+		{Type: "support_agent", Occasional: true},
+		{Type: "field_agent"},
+		{Type: "collaborator"},
+	})
+
+	profile := usage.profile(map[string]int64{seatFullTime: 2, seatOccasional: 5})
+
+	assert.Equal(t, int64(2), profile["seats_used_full_time"])
+	assert.Equal(t, int64(1), profile["seats_used_occasional"])
+	assert.Equal(t, int64(1), profile["seats_used_field_service"])
+	assert.Equal(t, int64(1), profile["seats_used_collaborators"])
+	assert.Equal(t, int64(0), profile["seats_available_full_time"])
+	assert.Equal(t, int64(4), profile["seats_available_occasional"])
+	assert.NotContains(t, profile, "seats_limit_field_service")
+	assert.Equal(t, int64(2), profile["agents_support_agent_full_time"])
+	assert.Equal(t, int64(1), profile["agents_support_agent_occasional"])
+}
+
+func TestSeatLimitsFor(t *testing.T) {
+	limits := []SeatLimit{
+		{Domain: "acme", Category: seatFullTime, Limit: 10},
+		{Category: seatFullTime, Limit: 25},
+		{Category: seatOccasional, Limit: 5},
+	}
+
+	assert.Equal(t, map[string]int64{seatFullTime: 10, seatOccasional: 5}, seatLimitsFor("acme", limits))
+	assert.Equal(t, map[string]int64{seatFullTime: 25, seatOccasional: 5}, seatLimitsFor("other", limits))
+}
+
+func TestCheckSeatsAvailableReusesTheSeatUsage(t *testing.T) {
+	var listed atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		listed.Add(1)
+		_, _ = w.Write([]byte(`[{"id":1,"type":"support_agent"}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	a := &account{domain: "acme", client: c, seatLimits: map[string]int64{seatFullTime: 3}}
+
+	require.NoError(t, a.checkSeatAvailable(ctx, seatFullTime))
+	require.NoError(t, a.checkSeatAvailable(ctx, seatFullTime))
+	assert.ErrorContains(t, a.checkSeatAvailable(ctx, seatFullTime), "no full_time seat available in account acme: 3 of 3 in use")
+	assert.Equal(t, int64(1), listed.Load())
+}
@@ -0,0 +1,175 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// slaPolicyCoveredEntitlement links an SLA policy to the groups whose tickets it applies to.
+const slaPolicyCoveredEntitlement = "covered"
+
+type slaPolicyBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (s *slaPolicyBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return s.resourceType
+}
+
+// List returns the SLA policies of the account.
+func (s *slaPolicyBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := s.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, slaPolicyResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	policies, nextPageToken, annotation, err := account.client.ListSlaPolicies(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, policy := range policies {
+		policyCopy := policy
+		policyResource, err := parseIntoSlaPolicyResource(&policyCopy, s.accounts.resourceID(account, policy.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, policyResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (s *slaPolicyBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		slaPolicyCoveredEntitlement,
+		entitlement.WithGrantableTo(groupResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Coverage", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Groups whose tickets the %s SLA policy applies to", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants links the policy to the groups it explicitly applies to. The grants only mirror the policy
+// conditions, so they are immutable. The default policy covers every ticket no other policy matches and
+// has no grants.
+func (s *slaPolicyBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, policyID, err := s.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	policies, err := s.accountPolicies(ctx, account)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, groupID := range policies[policyID].ApplicableTo.GroupIDs {
+		inScope, err := account.groupInScope(ctx, groupID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		if !inScope {
+			continue
+		}
+
+		rv = append(rv, grant.NewGrant(
+			resource,
+			slaPolicyCoveredEntitlement,
+			&v2.ResourceId{
+				ResourceType: groupResourceType.Id,
+				Resource:     s.accounts.resourceID(account, groupID),
+			},
+			grant.WithAnnotation(&v2.GrantImmutable{}),
+		))
+	}
+
+	return rv, "", nil, nil
+}
+
+// accountPolicies returns the SLA policies of the account by ID, read once per sync.
+func (s *slaPolicyBuilder) accountPolicies(ctx context.Context, account *account) (map[int64]client.SlaPolicy, error) {
+	return syncCached(account, "sla_policies", func() (map[int64]client.SlaPolicy, error) {
+		list, err := account.client.ListAllSlaPolicies(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		policies := make(map[int64]client.SlaPolicy, len(list))
+		for _, policy := range list {
+			policies[policy.ID] = policy
+		}
+
+		return policies, nil
+	})
+}
+
+func newSlaPolicyBuilder(accounts *accountSet) *slaPolicyBuilder {
+	return &slaPolicyBuilder{
+		resourceType: slaPolicyResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoSlaPolicyResource - This function parses an SLA policy into a Resource.
+func parseIntoSlaPolicyResource(policy *client.SlaPolicy, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	status := "Inactive"
+	if policy.Active {
+		status = "Active"
+	}
+
+	description := fmt.Sprintf("%s policy at position %d", status, policy.Position)
+	if policy.IsDefault {
+		description = fmt.Sprintf("%s default policy, applies to every ticket no other policy matches", status)
+	}
+	if policy.Description != "" {
+		description += ". " + policy.Description
+	}
+
+	return rs.NewResource(
+		policy.Name,
+		slaPolicyResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
@@ -0,0 +1,58 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlaPolicyBuilder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/sla_policies":
+			_, _ = w.Write([]byte(`[` +
+				`{"id":1,"name":"Default SLA","active":true,"is_default":true,"position":2},` +
+				`{"id":2,"name":"VIP","active":true,"position":1,"applicable_to":{"group_ids":[10,11]}}]`))
+		case "/api/v2/groups":
+			_, _ = w.Write([]byte(`[{"id":10,"name":"Billing"},{"id":11,"name":"Contractors"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	acme := &account{domain: "acme", client: c, filters: &Filters{ExcludeGroups: []string{"Contractors"}}}
+	builder := newSlaPolicyBuilder(newAccountSet(acme))
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "acme"}
+
+	resources, _, _, err := builder.List(ctx, parent, &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "Active default policy, applies to every ticket no other policy matches", resources[0].Description)
+	assert.Equal(t, "Active policy at position 1", resources[1].Description)
+
+	// The default policy has no grants.
+	grants, _, _, err := builder.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+
+	// Groups filtered out of the sync are left out.
+	grants, _, _, err = builder.Grants(ctx, resources[1], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, &v2.ResourceId{ResourceType: groupResourceType.Id, Resource: "10"}, grants[0].Principal.Id)
+	annos := annotations.Annotations(grants[0].Annotations)
+	assert.True(t, annos.Contains(&v2.GrantImmutable{}))
+}
@@ -0,0 +1,58 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotSkipsAgentsCreatedDuringTheExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/v2/account":
+			_, _ = w.Write([]byte(`{"account_name":"Acme"}`))
+		case r.URL.Path == "/api/v2/agents" && r.URL.Query().Get("per_page") == "50":
+			// The grants page over the agents again, after Bob was created.
+			_, _ = w.Write([]byte(`[{"id":1,"contact":{"email":"jane@acme.com"}},{"id":2,"contact":{"email":"bob@acme.com"}}]`))
+		case r.URL.Path == "/api/v2/agents":
+			_, _ = w.Write([]byte(`[{"id":1,"contact":{"name":"Jane","email":"jane@acme.com"}}]`))
+		case r.URL.Path == "/api/v2/agents/1":
+			_, _ = w.Write([]byte(`{"id":1,"role_ids":[7],"group_ids":[8]}`))
+		case r.URL.Path == "/api/v2/agents/2":
+			_, _ = w.Write([]byte(`{"id":2,"role_ids":[7],"group_ids":[8]}`))
+		case r.URL.Path == "/api/v2/roles":
+			_, _ = w.Write([]byte(`[{"id":7,"name":"Supervisor"}]`))
+		case r.URL.Path == "/api/v2/groups":
+			_, _ = w.Write([]byte(`[{"id":8,"name":"Billing"}]`))
+		case strings.HasPrefix(r.URL.Path, "/api/v2/groups/"):
+			_, _ = w.Write([]byte(`{"id":8,"name":"Billing","agent_ids":[1,2]}`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	d := &Connector{accounts: newTestAccountSet(c), grantsPageSize: 50}
+	snapshot, err := d.Snapshot(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, snapshot.Agents, 1)
+	assert.Equal(t, "jane@acme.com", snapshot.Agents[0].Email)
+	assert.Equal(t, []string{"Supervisor"}, snapshot.Agents[0].Roles)
+	assert.Equal(t, []string{"Billing"}, snapshot.Agents[0].Groups)
+
+	require.Len(t, snapshot.Memberships, 2)
+	for _, membership := range snapshot.Memberships {
+		assert.Equal(t, "1", membership.AgentID)
+	}
+}
@@ -0,0 +1,163 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+// solutionVisibleEntitlement is the entitlement of knowledge-base categories and folders held by whoever can read them.
+const solutionVisibleEntitlement = "visible"
+
+type solutionCategoryBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (s *solutionCategoryBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return s.resourceType
+}
+
+// List returns the knowledge-base categories of the account. Their folders are listed under them.
+func (s *solutionCategoryBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := s.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, solutionCategoryResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	categories, nextPageToken, annotation, err := account.client.ListSolutionCategories(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, category := range categories {
+		categoryCopy := category
+		categoryResource, err := parseIntoSolutionCategoryResource(&categoryCopy, s.accounts.resourceID(account, category.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, categoryResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+// Entitlements returns the visibility of the category. A category is visible to whoever can see one of its folders.
+func (s *solutionCategoryBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		solutionVisibleEntitlement,
+		entitlement.WithGrantableTo(companyResourceType, contactSegmentResourceType, solutionFolderResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Category Visibility", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Can see the %s knowledge-base category in the support portal", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants grants the category visibility to each of its folders, expanded to the companies and contact
+// segments the folder is visible to.
+func (s *solutionCategoryBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, categoryID, err := s.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, solutionFolderResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	folders, nextPageToken, annotation, err := account.client.ListSolutionFolders(ctx, categoryID, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, folder := range folders {
+		folderResource := &v2.Resource{
+			Id: &v2.ResourceId{
+				ResourceType: solutionFolderResourceType.Id,
+				Resource:     s.accounts.resourceID(account, folder.ID),
+			},
+		}
+
+		rv = append(rv, grant.NewGrant(
+			resource,
+			solutionVisibleEntitlement,
+			folderResource.Id,
+			grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{entitlement.NewEntitlementID(folderResource, solutionVisibleEntitlement)},
+			}),
+		))
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func newSolutionCategoryBuilder(accounts *accountSet) *solutionCategoryBuilder {
+	return &solutionCategoryBuilder{
+		resourceType: solutionCategoryResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoSolutionCategoryResource - This function parses a knowledge-base category into a Resource.
+func parseIntoSolutionCategoryResource(category *client.SolutionCategory, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	return rs.NewResource(
+		category.Name,
+		solutionCategoryResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(category.Description),
+		rs.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: solutionFolderResourceType.Id},
+		),
+	)
+}
@@ -0,0 +1,164 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type solutionFolderBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (s *solutionFolderBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return s.resourceType
+}
+
+// List returns the top-level folders of a knowledge-base category.
+func (s *solutionFolderBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil || parentResourceID.ResourceType != solutionCategoryResourceType.Id {
+		return nil, "", nil, nil
+	}
+
+	account, categoryID, err := s.accounts.parseResourceID(parentResourceID.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, solutionFolderResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	folders, nextPageToken, annotation, err := account.client.ListSolutionFolders(ctx, categoryID, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, folder := range folders {
+		folderCopy := folder
+		folderResource, err := parseIntoSolutionFolderResource(&folderCopy, s.accounts.resourceID(account, folder.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, folderResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (s *solutionFolderBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		solutionVisibleEntitlement,
+		entitlement.WithGrantableTo(companyResourceType, contactSegmentResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Folder Visibility", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Can read the articles of the %s knowledge-base folder in the support portal", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants returns the companies or contact segments a restricted folder is visible to. Folders visible to
+// everyone, to logged-in users or to agents only have no grants; their visibility is in the description.
+func (s *solutionFolderBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	var rv []*v2.Grant
+
+	account, folderID, err := s.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	folder, annotation, err := account.client.GetSolutionFolder(ctx, folderID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var principalType *v2.ResourceType
+	var principalIDs []int64
+	switch folder.Visibility {
+	case client.FolderVisibilityCompanies:
+		principalType, principalIDs = companyResourceType, folder.CompanyIDs
+	case client.FolderVisibilityContactSegments:
+		principalType, principalIDs = contactSegmentResourceType, folder.ContactSegmentIDs
+	}
+
+	for _, principalID := range principalIDs {
+		rv = append(rv, grant.NewGrant(resource, solutionVisibleEntitlement, &v2.ResourceId{
+			ResourceType: principalType.Id,
+			Resource:     s.accounts.resourceID(account, principalID),
+		}))
+	}
+
+	return rv, "", annotation, nil
+}
+
+func newSolutionFolderBuilder(accounts *accountSet) *solutionFolderBuilder {
+	return &solutionFolderBuilder{
+		resourceType: solutionFolderResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoSolutionFolderResource - This function parses a knowledge-base folder into a Resource.
+// The folder visibility leads its description.
+func parseIntoSolutionFolderResource(folder *client.SolutionFolder, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := fmt.Sprintf("Visible to %s", folderVisibilityName(folder.Visibility))
+	if folder.Description != "" {
+		description += ". " + folder.Description
+	}
+
+	return rs.NewResource(
+		folder.Name,
+		solutionFolderResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
+
+// folderVisibilityName translates the numeric visibility of a knowledge-base folder into who can read it.
+func folderVisibilityName(visibility int64) string {
+	switch visibility {
+	case client.FolderVisibilityAllUsers:
+		return "all users"
+	case client.FolderVisibilityLoggedInUsers:
+		return "logged-in users"
+	case client.FolderVisibilityAgents:
+		return "agents only"
+	case client.FolderVisibilityCompanies:
+		return "selected companies"
+	case client.FolderVisibilityBots:
+		return "bots"
+	case client.FolderVisibilityContactSegments:
+		return "selected contact segments"
+	case client.FolderVisibilityCompanySegments:
+		return "selected company segments"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,120 @@
+package connector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSolutionAccounts serves the folders of category 1 with every visibility, folder N having visibility N,
+// sharing it with companies 10 and 11 and contact segments 20 and 21.
+func newTestSolutionAccounts(t *testing.T) *accountSet {
+	t.Helper()
+
+	folder := func(visibility int64) string {
+		return fmt.Sprintf(`{"id":%d,"name":"Folder %d","visibility":%d,"company_ids":[10,11],"contact_segment_ids":[20,21]}`,
+			visibility, visibility, visibility)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/v2/solutions/categories/1/folders":
+			var folders []string
+			for visibility := client.FolderVisibilityAllUsers; visibility <= client.FolderVisibilityCompanySegments; visibility++ {
+				folders = append(folders, folder(int64(visibility)))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(folders, ",") + "]"))
+		case strings.HasPrefix(r.URL.Path, "/api/v2/solutions/folders/"):
+			var visibility int64
+			_, err := fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/api/v2/solutions/folders/"), "%d", &visibility)
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(folder(visibility)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	return newAccountSet(&account{domain: "acme", client: c})
+}
+
+func TestSolutionCategoryGrants(t *testing.T) {
+	accounts := newTestSolutionAccounts(t)
+	category := &v2.Resource{Id: &v2.ResourceId{ResourceType: solutionCategoryResourceType.Id, Resource: "1"}}
+
+	grants, nextToken, _, err := newSolutionCategoryBuilder(accounts).Grants(ctx, category, &pagination.Token{})
+	require.NoError(t, err)
+	assert.Empty(t, nextToken)
+
+	// Every folder, whatever its visibility, passes the readers it has on to the category.
+	require.Len(t, grants, 7)
+	for i, g := range grants {
+		folderID := fmt.Sprint(i + 1)
+		assert.Equal(t, &v2.ResourceId{ResourceType: solutionFolderResourceType.Id, Resource: folderID}, g.Principal.Id)
+
+		expandable := &v2.GrantExpandable{}
+		annos := annotations.Annotations(g.Annotations)
+		ok, err := annos.Pick(expandable)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, []string{"solution_folder:" + folderID + ":visible"}, expandable.EntitlementIds)
+	}
+}
+
+func TestSolutionFolderGrants(t *testing.T) {
+	accounts := newTestSolutionAccounts(t)
+	builder := newSolutionFolderBuilder(accounts)
+
+	tests := []struct {
+		visibility int64
+		principals []*v2.ResourceId
+	}{
+		{visibility: client.FolderVisibilityAllUsers},
+		{visibility: client.FolderVisibilityLoggedInUsers},
+		{visibility: client.FolderVisibilityAgents},
+		{visibility: client.FolderVisibilityCompanies, principals: []*v2.ResourceId{
+			{ResourceType: companyResourceType.Id, Resource: "10"},
+			{ResourceType: companyResourceType.Id, Resource: "11"},
+		}},
+		{visibility: client.FolderVisibilityBots},
+		{visibility: client.FolderVisibilityContactSegments, principals: []*v2.ResourceId{
+			{ResourceType: contactSegmentResourceType.Id, Resource: "20"},
+			{ResourceType: contactSegmentResourceType.Id, Resource: "21"},
+		}},
+		// Company segments are not synced, so folders shared with them have no grants.
+		{visibility: client.FolderVisibilityCompanySegments},
+	}
+
+	for _, tt := range tests {
+		t.Run(folderVisibilityName(tt.visibility), func(t *testing.T) {
+			folder := &v2.Resource{Id: &v2.ResourceId{ResourceType: solutionFolderResourceType.Id, Resource: fmt.Sprint(tt.visibility)}}
+
+			grants, _, _, err := builder.Grants(ctx, folder, &pagination.Token{})
+			require.NoError(t, err)
+
+			var principals []*v2.ResourceId
+			for _, g := range grants {
+				assert.Equal(t, "solution_folder:"+folder.Id.Resource+":visible", g.Entitlement.Id)
+				principals = append(principals, g.Principal.Id)
+			}
+			assert.Equal(t, tt.principals, principals)
+		})
+	}
+}
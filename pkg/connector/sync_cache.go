@@ -0,0 +1,55 @@
+package connector
+
+import (
+	"sync"
+)
+
+// syncCacheEntry holds one list read from an account during a sync. Every entry has its own lock,
+// so that loading one entry can read another.
+type syncCacheEntry struct {
+	mtx    sync.Mutex
+	loaded bool
+	value  any
+}
+
+// startSync drops what the builders read from the account during the previous sync, agent details
+// included. The account builder calls it when it lists the accounts, which every sync starts with.
+func (a *account) startSync() {
+	a.syncMutex.Lock()
+	a.syncCache = nil
+	a.syncMutex.Unlock()
+
+	if a.agentDetails != nil {
+		a.agentDetails.reset()
+	}
+}
+
+// syncCached returns what load reads from the account, calling it once per sync for each key.
+// Failed loads are not kept, so the next caller tries again.
+func syncCached[T any](a *account, key string, load func() (T, error)) (T, error) {
+	a.syncMutex.Lock()
+	if a.syncCache == nil {
+		a.syncCache = make(map[string]*syncCacheEntry)
+	}
+	entry, ok := a.syncCache[key]
+	if !ok {
+		entry = &syncCacheEntry{}
+		a.syncCache[key] = entry
+	}
+	a.syncMutex.Unlock()
+
+	entry.mtx.Lock()
+	defer entry.mtx.Unlock()
+
+	if entry.loaded {
+		return entry.value.(T), nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	entry.value, entry.loaded = value, true
+
+	return value, nil
+}
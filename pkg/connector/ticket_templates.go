@@ -0,0 +1,127 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type ticketTemplateBuilder struct {
+	resourceType *v2.ResourceType
+	accounts     *accountSet
+}
+
+func (t *ticketTemplateBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return t.resourceType
+}
+
+// List returns the ticket templates of the account.
+func (t *ticketTemplateBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := t.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	bag, pageToken, err := getToken(pToken, ticketTemplateResourceType)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	templates, nextPageToken, annotation, err := account.client.ListTicketTemplates(ctx, client.PageOptions{
+		Cursor:  pageToken,
+		PerPage: pToken.Size,
+	})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	err = bag.Next(nextPageToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, template := range templates {
+		templateCopy := template
+		templateResource, err := parseIntoTicketTemplateResource(&templateCopy, t.accounts.resourceID(account, template.ID), parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, templateResource)
+	}
+
+	nextPageToken, err = bag.Marshal()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, nextPageToken, annotation, nil
+}
+
+func (t *ticketTemplateBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	var rv []*v2.Entitlement
+
+	rv = append(rv, entitlement.NewPermissionEntitlement(
+		resource,
+		sharedItemViewerEntitlement,
+		entitlement.WithGrantableTo(userResourceType, groupResourceType),
+		entitlement.WithDisplayName(fmt.Sprintf("%s Template Viewer", resource.DisplayName)),
+		entitlement.WithDescription(fmt.Sprintf("Can create tickets from the %s template", resource.DisplayName)),
+	))
+
+	return rv, "", nil, nil
+}
+
+// Grants returns the owner of a personal template or the groups a template is shared with.
+func (t *ticketTemplateBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	account, templateID, err := t.accounts.parseResourceID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	template, annotation, err := account.client.GetTicketTemplate(ctx, templateID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	rv, err := visibilityGrants(ctx, t.accounts, account, resource, sharedItemViewerEntitlement, template.Visibility, template.GroupIDs, []int64{template.UserID})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return rv, "", annotation, nil
+}
+
+func newTicketTemplateBuilder(accounts *accountSet) *ticketTemplateBuilder {
+	return &ticketTemplateBuilder{
+		resourceType: ticketTemplateResourceType,
+		accounts:     accounts,
+	}
+}
+
+// parseIntoTicketTemplateResource - This function parses a ticket template into a Resource.
+// Who the template is visible to leads its description.
+func parseIntoTicketTemplateResource(template *client.TicketTemplate, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := fmt.Sprintf("Visible to %s", visibilityName(template.Visibility))
+	if template.Description != "" {
+		description += ". " + template.Description
+	}
+
+	return rs.NewResource(
+		template.Name,
+		ticketTemplateResourceType,
+		resourceID,
+		rs.WithParentResourceID(parentResourceID),
+		rs.WithDescription(description),
+	)
+}
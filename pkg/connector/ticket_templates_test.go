@@ -0,0 +1,90 @@
+package connector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketTemplateBuilder(t *testing.T) {
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	templates := map[string]string{
+		"1": `{"id":1,"name":"Refund","visibility":1,"user_id":42,"group_ids":[3]}`,
+		"2": `{"id":2,"name":"Escalation","description":"Hand over to tier 2","visibility":2,"group_ids":[3,4],"user_id":42}`,
+		"3": `{"id":3,"name":"Greeting","visibility":0,"user_id":42}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/ticket_templates":
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`[` + templates["3"] + `]`))
+				return
+			}
+			w.Header().Set("Link", `</api/v2/ticket_templates?per_page=2&page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[` + templates["1"] + `,` + templates["2"] + `]`))
+		case "/api/v2/ticket_templates/1", "/api/v2/ticket_templates/2", "/api/v2/ticket_templates/3":
+			_, _ = w.Write([]byte(templates[r.URL.Path[len("/api/v2/ticket_templates/"):]]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(ctx, client.WithBaseURL(server.URL), client.WithBearerToken("token"))
+	require.NoError(t, err)
+
+	builder := newTicketTemplateBuilder(newAccountSet(&account{domain: "acme", client: c}))
+	parent := &v2.ResourceId{ResourceType: accountResourceType.Id, Resource: "acme"}
+
+	var resources []*v2.Resource
+	token := &pagination.Token{Size: 2}
+	for {
+		page, nextToken, _, err := builder.List(ctx, parent, token)
+		require.NoError(t, err)
+		resources = append(resources, page...)
+		if nextToken == "" {
+			break
+		}
+		token = &pagination.Token{Size: 2, Token: nextToken}
+	}
+	require.Len(t, resources, 3)
+	assert.Equal(t, "Visible to selected agents", resources[0].Description)
+	assert.Equal(t, "Visible to selected groups. Hand over to tier 2", resources[1].Description)
+
+	// A personal template is granted to its owner only, whatever groups it lists.
+	grants, _, _, err := builder.Grants(ctx, resources[0], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, "ticket_template:1:viewer", grants[0].Entitlement.Id)
+	assert.Equal(t, &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "42"}, grants[0].Principal.Id)
+
+	// A template shared with groups is granted to each group, expanded to its members.
+	grants, _, _, err = builder.Grants(ctx, resources[1], &pagination.Token{})
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	for i, groupID := range []string{"3", "4"} {
+		assert.Equal(t, &v2.ResourceId{ResourceType: groupResourceType.Id, Resource: groupID}, grants[i].Principal.Id)
+
+		expandable := &v2.GrantExpandable{}
+		annos := annotations.Annotations(grants[i].Annotations)
+		ok, err := annos.Pick(expandable)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, []string{"group:" + groupID + ":member"}, expandable.EntitlementIds)
+	}
+
+	// A template every agent sees has no grants.
+	grants, _, _, err = builder.Grants(ctx, resources[2], &pagination.Token{})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}
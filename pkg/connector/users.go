@@ -2,17 +2,34 @@ package connector
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/conductorone/baton-freshdesk/pkg/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	rs "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// userAgentEntitlement is held by every user who is an agent. Revoking it downgrades the agent to a contact.
+const userAgentEntitlement = "agent"
+
+// AgentDefaults are the roles and groups, by name or by Freshdesk ID, given to the contacts converted into agents.
+// Without roles Freshdesk assigns its default Agent role.
+type AgentDefaults struct {
+	Roles  []string
+	Groups []string
+}
+
 type userBuilder struct {
-	resourceType *v2.ResourceType
-	client       *client.FreshdeskClient
+	resourceType  *v2.ResourceType
+	accounts      *accountSet
+	agentDefaults AgentDefaults
 }
 
 func (u *userBuilder) ResourceType(_ context.Context) *v2.ResourceType {
@@ -23,14 +40,22 @@ func (u *userBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 // Users include a UserTrait because they are the 'shape' of a standard user.
 func (u *userBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
 	var rv []*v2.Resource
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	account, err := u.accounts.fromAccountResourceID(parentResourceID)
+	if err != nil {
+		return nil, "", nil, err
+	}
 
 	bag, pageToken, err := getToken(pToken, userResourceType)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
-	agents, nextPageToken, annotation, err := u.client.ListAgents(ctx, client.PageOptions{
-		Page:    pageToken,
+	agents, nextPageToken, annotation, err := account.client.ListAgents(ctx, client.PageOptions{
+		Cursor:  pageToken,
 		PerPage: pToken.Size,
 	})
 	if err != nil {
@@ -43,8 +68,12 @@ func (u *userBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 	}
 
 	for _, agent := range agents {
+		if !account.filters.agentIncluded(&agent) {
+			continue
+		}
+
 		agentCopy := agent
-		userResource, err := parseIntoUserResource(&agentCopy, parentResourceID)
+		userResource, err := parseIntoUserResource(&agentCopy, u.accounts.resourceID(account, agent.ID), parentResourceID)
 		if err != nil {
 			return nil, "", nil, err
 		}
@@ -60,16 +89,28 @@ func (u *userBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 }
 
 // parseIntoUserResource - This function parses an Agent (users from Freshdesk) into a User Resource.
-func parseIntoUserResource(agent *client.Agent, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
-	var userStatus = v2.UserTrait_Status_STATUS_ENABLED
+// The same person has one user per account; C1 matches them into one identity through the primary email.
+// A deleted agent only remains as a contact, so it no longer holds a license nor a ticket scope.
+func parseIntoUserResource(agent *client.Agent, resourceID string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	userStatus := agentStatus(agent)
 
 	profile := map[string]interface{}{
-		"user_id":    agent.ID,
-		"login":      agent.Contact.Email,
-		"first_name": agent.Contact.Name,
-		"last_name":  agent.Contact.Name,
-		"email":      agent.Contact.Email,
-		"is_agent":   true,
+		"user_id":     agent.ID,
+		"login":       agent.Contact.Email,
+		"first_name":  agent.Contact.Name,
+		"last_name":   agent.Contact.Name,
+		"email":       agent.Contact.Email,
+		"is_agent":    true,
+		"agent_type":  agent.Type,
+		"deactivated": agent.Deactivated,
+	}
+
+	if userStatus == v2.UserTrait_Status_STATUS_DELETED {
+		profile["is_agent"] = false
+		profile["deleted"] = true
+	} else {
+		profile["ticket_scope"] = ticketScopeName(agent.TicketScope)
+		profile["license_type"] = licenseType(agent)
 	}
 
 	userTraits := []rs.UserTraitOption{
@@ -79,6 +120,10 @@ func parseIntoUserResource(agent *client.Agent, parentResourceID *v2.ResourceId)
 		rs.WithEmail(agent.Contact.Email, true),
 	}
 
+	if !agent.Contact.LastLoginAt.IsZero() {
+		userTraits = append(userTraits, rs.WithLastLogin(agent.Contact.LastLoginAt))
+	}
+
 	displayName := agent.Contact.Name
 	if displayName == "" {
 		displayName = agent.Contact.Email
@@ -87,7 +132,7 @@ func parseIntoUserResource(agent *client.Agent, parentResourceID *v2.ResourceId)
 	ret, err := rs.NewUserResource(
 		displayName,
 		userResourceType,
-		agent.ID,
+		resourceID,
 		userTraits,
 		rs.WithParentResourceID(parentResourceID),
 	)
@@ -98,19 +143,321 @@ func parseIntoUserResource(agent *client.Agent, parentResourceID *v2.ResourceId)
 	return ret, nil
 }
 
-// Entitlements always returns an empty slice for users.
-func (u *userBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
-	return nil, "", nil, nil
+// agentStatus returns the status of an agent: deleted once only its soft-deleted contact is left,
+// disabled while deactivated or blocked, enabled otherwise.
+func agentStatus(agent *client.Agent) v2.UserTrait_Status_Status {
+	if agent.Contact.Deleted {
+		return v2.UserTrait_Status_STATUS_DELETED
+	}
+
+	if agent.Deactivated {
+		return v2.UserTrait_Status_STATUS_DISABLED
+	}
+
+	return contactStatus(&agent.Contact)
+}
+
+// contactStatus returns the status of a contact: deleted once soft-deleted, disabled while blocked, enabled otherwise.
+func contactStatus(contact *client.Contact) v2.UserTrait_Status_Status {
+	switch {
+	case contact.Deleted:
+		return v2.UserTrait_Status_STATUS_DELETED
+	case contact.Blocked:
+		return v2.UserTrait_Status_STATUS_DISABLED
+	default:
+		return v2.UserTrait_Status_STATUS_ENABLED
+	}
+}
+
+// ticketScopeName translates the numeric ticket_scope of an agent into the permission it stands for.
+func ticketScopeName(ticketScope int64) string {
+	switch ticketScope {
+	case 1:
+		return "global_access"
+	case 2:
+		return "group_access"
+	case 3:
+		return "restricted_access"
+	default:
+		return "unknown"
+	}
+}
+
+// ticketScopeValue is the reverse of ticketScopeName. An empty name stands for global access.
+func ticketScopeValue(name string) (int64, error) {
+	switch name {
+	case "", "global_access":
+		return 1, nil
+	case "group_access":
+		return 2, nil
+	case "restricted_access":
+		return 3, nil
+	default:
+		return 0, fmt.Errorf("baton-freshdesk: unknown ticket scope %s, expected global_access, group_access or restricted_access", name)
+	}
+}
+
+// licenseType returns the seat an agent consumes: occasional agents use day passes instead of a full-time license.
+func licenseType(agent *client.Agent) string {
+	if agent.Occasional {
+		return "occasional"
+	}
+
+	return "full_time"
+}
+
+// Entitlements returns the agent entitlement of a user, which only the user itself holds.
+func (u *userBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	agentOptions := []entitlement.EntitlementOption{
+		entitlement.WithGrantableTo(userResourceType),
+		entitlement.WithDescription(fmt.Sprintf("%s is a support agent. Revoking it downgrades the agent to a contact", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("%s agent", resource.DisplayName)),
+	}
+
+	return []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(resource, userAgentEntitlement, agentOptions...),
+	}, "", nil, nil
+}
+
+// Grants returns the agent grant of the users who are still agents, that is all of them but the deleted ones.
+func (u *userBuilder) Grants(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	if !isAgentUser(resource) {
+		return nil, "", nil, nil
+	}
+
+	return []*v2.Grant{
+		grant.NewGrant(resource, userAgentEntitlement, resource.Id),
+	}, "", nil, nil
+}
+
+// Grant only acknowledges the agent entitlement of a user who is an agent. Agents are created, and contacts
+// converted into agents, with CreateAccount.
+func (u *userBuilder) Grant(_ context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	if principal.Id.ResourceType != userResourceType.Id || principal.Id.Resource != entitlement.Resource.Id.Resource {
+		return nil, fmt.Errorf("baton-freshdesk: the agent entitlement of a user can only be granted to the user itself")
+	}
+
+	if !isAgentUser(principal) {
+		return nil, fmt.Errorf("baton-freshdesk: %s is no longer an agent, create their account again instead", principal.DisplayName)
+	}
+
+	return annotations.New(&v2.GrantAlreadyExists{}), nil
+}
+
+// Revoke downgrades an agent to a contact, freeing their seat. The contact keeps the ID of the agent.
+func (u *userBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	if grant.Principal.Id.ResourceType != userResourceType.Id || grant.Principal.Id.Resource != grant.Entitlement.Resource.Id.Resource {
+		return nil, fmt.Errorf("baton-freshdesk: the agent entitlement of a user can only be revoked from the user itself")
+	}
+
+	return demoteAgent(ctx, u.accounts, grant.Principal.Id.Resource)
+}
+
+// demoteAgent downgrades the agent of resourceID to a contact, freeing their seat. The contact keeps the ID
+// of the agent. An agent already downgraded is reported as such.
+func demoteAgent(ctx context.Context, accounts *accountSet, resourceID string) (annotations.Annotations, error) {
+	account, agentID, err := accounts.parseResourceID(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, err := account.client.DeleteAgent(ctx, agentID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// isAgentUser reports whether a user resource stands for an agent rather than a deleted one.
+func isAgentUser(resource *v2.Resource) bool {
+	userTrait, err := rs.GetUserTrait(resource)
+	if err != nil {
+		return false
+	}
+
+	return userTrait.Profile.GetFields()["is_agent"].GetBoolValue()
+}
+
+// CreateAccount creates a support agent, who receives the Freshdesk activation email. The profile may set
+// the name, the account domain (the first account by default), the license_type (full_time or occasional)
+// and the ticket_scope of the agent. It refuses when the account has no seat of that license left.
+// When an agent already has the email, that agent is returned rather than created again; when a customer
+// contact has it, the creation is refused.
+func (u *userBuilder) CreateAccount(
+	ctx context.Context,
+	accountInfo *v2.AccountInfo,
+	_ *v2.CredentialOptions,
+) (connectorbuilder.CreateAccountResponse, []*v2.PlaintextData, annotations.Annotations, error) {
+	profile := accountInfo.GetProfile().AsMap()
+
+	email := accountEmail(accountInfo)
+	if email == "" {
+		return nil, nil, nil, fmt.Errorf("baton-freshdesk: an email is required to create an agent")
+	}
+
+	account := u.accounts.accounts[0]
+	if accountDomain, ok := profile["domain"].(string); ok && accountDomain != "" {
+		account, ok = u.accounts.byDomain[accountDomain]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("baton-freshdesk: unknown account %s", accountDomain)
+		}
+	}
+
+	license, _ := profile["license_type"].(string)
+	if license != "" && license != seatFullTime && license != seatOccasional {
+		return nil, nil, nil, fmt.Errorf("baton-freshdesk: unknown license type %s, expected %s or %s", license, seatFullTime, seatOccasional)
+	}
+
+	scopeName, _ := profile["ticket_scope"].(string)
+	ticketScope, err := ticketScopeValue(scopeName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	existing, annotation, err := account.client.GetAgentByEmail(ctx, email)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The email already belongs to an agent: hand it back instead of creating a duplicate.
+	if existing != nil {
+		resource, err := parseIntoUserResource(existing, u.accounts.resourceID(account, existing.ID), accountResourceID(account))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return &v2.CreateAccountResponse_SuccessResult{
+			Resource:              resource,
+			IsCreateAccountResult: false,
+		}, nil, annotation, nil
+	}
+
+	// Freshdesk refuses to create an agent over an existing contact: the contact is converted instead.
+	contact, err := account.client.GetContactByEmail(ctx, email)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if contact != nil {
+		return u.convertContact(ctx, account, contact, ticketScope, license == seatOccasional)
+	}
+
+	request := &client.CreateAgentRequest{
+		Email:       email,
+		TicketScope: ticketScope,
+		Occasional:  license == seatOccasional,
+	}
+	request.Name, _ = profile["name"].(string)
+
+	err = account.checkSeatAvailable(ctx, licenseType(&client.Agent{Occasional: request.Occasional}))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	agent, annotation, err := account.client.CreateAgent(ctx, request)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	resource, err := parseIntoUserResource(agent, u.accounts.resourceID(account, agent.ID), accountResourceID(account))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &v2.CreateAccountResponse_SuccessResult{
+		Resource:              resource,
+		IsCreateAccountResult: true,
+	}, nil, annotation, nil
+}
+
+// convertContact converts a contact into an agent with the default roles and groups. The agent keeps the ID
+// of the contact, so the identity synced as a contact carries over to the user.
+func (u *userBuilder) convertContact(
+	ctx context.Context,
+	account *account,
+	contact *client.Contact,
+	ticketScope int64,
+	occasional bool,
+) (connectorbuilder.CreateAccountResponse, []*v2.PlaintextData, annotations.Annotations, error) {
+	request := &client.MakeAgentRequest{
+		TicketScope: ticketScope,
+		Occasional:  occasional,
+	}
+
+	if len(u.agentDefaults.Roles) > 0 || len(u.agentDefaults.Groups) > 0 {
+		roleIDs, groupIDs, err := account.roleAndGroupIDsByName(ctx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		request.RoleIDs, err = resolveNamedIDs(u.agentDefaults.Roles, roleIDs, "role", contact.Email)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		request.GroupIDs, err = resolveNamedIDs(u.agentDefaults.Groups, groupIDs, "group", contact.Email)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	err := account.checkSeatAvailable(ctx, licenseType(&client.Agent{Occasional: occasional}))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	agent, annotation, err := account.client.MakeAgent(ctx, contact.ID, request)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if agent.ID == 0 {
+		agent.ID = contact.ID
+	}
+
+	resource, err := parseIntoUserResource(agent, u.accounts.resourceID(account, agent.ID), accountResourceID(account))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &v2.CreateAccountResponse_SuccessResult{
+		Resource:              resource,
+		IsCreateAccountResult: true,
+	}, nil, annotation, nil
+}
+
+// CreateAccountCapabilityDetails reports that agents are created without a password: they set it from the activation email.
+func (u *userBuilder) CreateAccountCapabilityDetails(_ context.Context) (*v2.CredentialDetailsAccountProvisioning, annotations.Annotations, error) {
+	return &v2.CredentialDetailsAccountProvisioning{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+	}, nil, nil
 }
 
-// Grants always returns an empty slice for users since they don't have any entitlements.
-func (u *userBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
-	return nil, "", nil, nil
+// accountEmail returns the primary email of the account to create, falling back to the first email or the email profile field.
+func accountEmail(accountInfo *v2.AccountInfo) string {
+	for _, email := range accountInfo.GetEmails() {
+		if email.GetIsPrimary() {
+			return email.GetAddress()
+		}
+	}
+
+	if emails := accountInfo.GetEmails(); len(emails) > 0 {
+		return emails[0].GetAddress()
+	}
+
+	email, _ := accountInfo.GetProfile().AsMap()["email"].(string)
+	return email
 }
 
-func newUserBuilder(c *client.FreshdeskClient) *userBuilder {
+func newUserBuilder(accounts *accountSet, agentDefaults AgentDefaults) *userBuilder {
 	return &userBuilder{
-		resourceType: userResourceType,
-		client:       c,
+		resourceType:  userResourceType,
+		accounts:      accounts,
+		agentDefaults: agentDefaults,
 	}
 }
@@ -0,0 +1,16 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentStatus(t *testing.T) {
+	assert.Equal(t, v2.UserTrait_Status_STATUS_ENABLED, agentStatus(&client.Agent{}))
+	assert.Equal(t, v2.UserTrait_Status_STATUS_DISABLED, agentStatus(&client.Agent{Deactivated: true}))
+	assert.Equal(t, v2.UserTrait_Status_STATUS_DISABLED, agentStatus(&client.Agent{Contact: client.Contact{Blocked: true}}))
+	assert.Equal(t, v2.UserTrait_Status_STATUS_DELETED, agentStatus(&client.Agent{Deactivated: true, Contact: client.Contact{Deleted: true}}))
+}
@@ -0,0 +1,84 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+)
+
+// sharedItemViewerEntitlement is held by whoever can see an item agents share with each other,
+// such as a canned response or a ticket template.
+const sharedItemViewerEntitlement = "viewer"
+
+// visibilityName translates the visibility of a shared item into who can see it.
+func visibilityName(visibility int64) string {
+	switch visibility {
+	case client.VisibilityAllAgents:
+		return "all agents"
+	case client.VisibilityPersonal:
+		return "selected agents"
+	case client.VisibilityGroups:
+		return "selected groups"
+	default:
+		return "unknown"
+	}
+}
+
+// visibilityGrants grants entitlementName of resource to whoever can see a shared item: the agents a personal
+// item belongs to, such as its owner, or the groups it is shared with, expanded to their members. Items visible
+// to every agent have no grants, and neither have agents or groups filtered out of the sync.
+func visibilityGrants(
+	ctx context.Context,
+	accounts *accountSet,
+	account *account,
+	resource *v2.Resource,
+	entitlementName string,
+	visibility int64,
+	groupIDs []int64,
+	agentIDs []int64,
+) ([]*v2.Grant, error) {
+	var rv []*v2.Grant
+
+	switch visibility {
+	case client.VisibilityPersonal:
+		for _, agentID := range agentIDs {
+			if agentID == 0 {
+				continue
+			}
+
+			inScope, err := account.agentInScope(ctx, agentID)
+			if err != nil {
+				return nil, err
+			}
+
+			if !inScope {
+				continue
+			}
+
+			rv = append(rv, grant.NewGrant(resource, entitlementName, &v2.ResourceId{
+				ResourceType: userResourceType.Id,
+				Resource:     accounts.resourceID(account, agentID),
+			}))
+		}
+	case client.VisibilityGroups:
+		for _, groupID := range groupIDs {
+			inScope, err := account.groupInScope(ctx, groupID)
+			if err != nil {
+				return nil, err
+			}
+
+			if !inScope {
+				continue
+			}
+
+			rv = append(rv, newGroupExpandedGrant(resource, entitlementName, &v2.ResourceId{
+				ResourceType: groupResourceType.Id,
+				Resource:     accounts.resourceID(account, groupID),
+			}))
+		}
+	}
+
+	return rv, nil
+}
@@ -0,0 +1,39 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-freshdesk/pkg/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisibilityGrants(t *testing.T) {
+	acme := &account{domain: "acme"}
+	accounts := newAccountSet(acme)
+	resource := &v2.Resource{Id: &v2.ResourceId{ResourceType: ticketTemplateResourceType.Id, Resource: "7"}}
+
+	grants, err := visibilityGrants(context.Background(), accounts, acme, resource, sharedItemViewerEntitlement, client.VisibilityPersonal, nil, []int64{42})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "42"}, grants[0].Principal.Id)
+
+	grants, err = visibilityGrants(context.Background(), accounts, acme, resource, sharedItemViewerEntitlement, client.VisibilityGroups, []int64{3, 4}, []int64{42})
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, &v2.ResourceId{ResourceType: groupResourceType.Id, Resource: "3"}, grants[0].Principal.Id)
+
+	expandable := &v2.GrantExpandable{}
+	annos := annotations.Annotations(grants[0].Annotations)
+	ok, err := annos.Pick(expandable)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, []string{"group:3:member"}, expandable.EntitlementIds)
+
+	grants, err = visibilityGrants(context.Background(), accounts, acme, resource, sharedItemViewerEntitlement, client.VisibilityAllAgents, []int64{3}, []int64{42})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}